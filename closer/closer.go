@@ -3,6 +3,7 @@ package closer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -10,10 +11,13 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+
+	"github.com/defany/platcom/v2/pkg/perr"
 )
 
 const shutdownTimeout = 5 * time.Second
@@ -56,6 +60,9 @@ type Closer struct {
 
 	isGlobal bool
 
+	// signaled holds the os.Signal shutdown was initiated by, if any
+	signaled atomic.Value
+
 	funcs []closeItem
 }
 
@@ -81,6 +88,7 @@ func ToCloseNamed(name string, f func(context.Context) error) { defaultCloser.To
 func Go(fn func(context.Context) error) *Task                 { return defaultCloser.Go(fn) }
 func Close(ctx context.Context) error                         { return defaultCloser.Close(ctx) }
 func Wait() error                                             { return defaultCloser.Wait() }
+func Main(fn func(context.Context) error)                     { defaultCloser.Main(fn) }
 
 func (t *Task) After(fn func(context.Context) error) *Task { return t.With(fn) }
 
@@ -166,7 +174,7 @@ func (c *Closer) Go(fn func(context.Context) error) *Task {
 		err := fn(ctx)
 		if err != nil {
 			c.setFirstErr(err)
-			c.initiateShutdown()
+			go c.initiateShutdown()
 		}
 		return err
 	})
@@ -180,7 +188,7 @@ func (t *Task) With(fn func(context.Context) error) *Task {
 		err := fn(t.c.grpCtx)
 		if err != nil {
 			t.c.setFirstErr(err)
-			t.c.initiateShutdown()
+			go t.c.initiateShutdown()
 		}
 		return err
 	})
@@ -309,13 +317,38 @@ func (c *Closer) Wait() error {
 	return c.firstErr
 }
 
+// Main runs fn as a task, waits for shutdown, prints the error if any and exits with the code mapped by perr.ExitCode.
+// fn returning context.Canceled after shutdown was initiated isn't an error, the process exits with the code
+// perr.ExitSignal maps the signal to if shutdown was initiated by one, e.g. 143 for SIGTERM, and with perr.ExitOK otherwise.
+func (c *Closer) Main(fn func(context.Context) error) {
+	c.Go(func(ctx context.Context) error {
+		err := fn(ctx)
+		if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+			return nil
+		}
+		return err
+	})
+
+	err := c.Wait()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+
+	code := perr.ExitCode(err)
+	if sig, ok := c.signaled.Load().(os.Signal); err == nil && ok {
+		code = perr.ExitSignal(sig)
+	}
+	os.Exit(code)
+}
+
 func (c *Closer) handleSignals(signals ...os.Signal) {
 	ch := make(chan os.Signal, len(signals))
 	signal.Notify(ch, signals...)
 	defer signal.Stop(ch)
 	select {
-	case <-ch:
-		c.logger.Info("signal received, initiating shutdown")
+	case sig := <-ch:
+		c.logger.Info("signal received, initiating shutdown", slog.String("signal", sig.String()))
+		c.signaled.Store(sig)
 		c.initiateShutdown()
 	case <-c.done:
 	}
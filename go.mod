@@ -3,19 +3,24 @@ module github.com/defany/platcom/v2
 go 1.22
 
 require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/caarlos0/env/v10 v10.0.0
 	github.com/dsbasko/go-cfg v1.2.0
 	github.com/gookit/validate v1.5.2
+	github.com/joho/godotenv v1.5.1
 	github.com/rakyll/statik v0.1.7
-	golang.org/x/sync v0.5.0
+	golang.org/x/sync v0.7.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.33.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/BurntSushi/toml v1.3.2 // indirect
-	github.com/caarlos0/env/v10 v10.0.0 // indirect
 	github.com/gookit/filter v1.2.1 // indirect
 	github.com/gookit/goutil v0.6.15 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	golang.org/x/text v0.14.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
 )
@@ -0,0 +1,80 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/defany/platcom/v2/pkg/internal/reflectx"
+)
+
+// applyDefaults sets fields of cfg from their `default:"..."` tags.
+func applyDefaults(cfg any) error {
+	return walkFields(reflect.ValueOf(cfg), func(path string, field reflect.StructField, value reflect.Value) error {
+		raw, ok := field.Tag.Lookup("default")
+		if !ok {
+			return nil
+		}
+
+		if err := reflectx.SetValue(value, raw); err != nil {
+			return fmt.Errorf("failed to set default value of field %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+/*
+keepBools calls read and restores bool fields of cfg read has no value for, as reported by isSet
+
+Readers of go-cfg write every bool field, even ones without a flag or a key in a .env file, so without it
+a field tagged `default:"true"` would be reset to false.
+*/
+func keepBools(cfg any, read func() error, isSet func(field reflect.StructField) bool) error {
+	bools := make(map[string]bool)
+
+	_ = walkFields(reflect.ValueOf(cfg), func(path string, _ reflect.StructField, value reflect.Value) error {
+		if value.Kind() == reflect.Bool {
+			bools[path] = value.Bool()
+		}
+
+		return nil
+	})
+
+	if err := read(); err != nil {
+		return err
+	}
+
+	return walkFields(reflect.ValueOf(cfg), func(path string, field reflect.StructField, value reflect.Value) error {
+		if b, ok := bools[path]; ok && !isSet(field) {
+			value.SetBool(b)
+		}
+
+		return nil
+	})
+}
+
+// flagPassed reports whether args have a value for the flag of the field, in any form pflag accepts.
+func flagPassed(args []string, field reflect.StructField) bool {
+	long, short := field.Tag.Get("flag"), field.Tag.Get("s-flag")
+
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			if name, _, _ := strings.Cut(arg[2:], "="); long != "" && name == long {
+				return true
+			}
+
+			continue
+		}
+
+		if short != "" && strings.HasPrefix(arg, "-"+short) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package conf_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	conf "github.com/defany/platcom/v2/pkg/config"
+)
+
+type boolConfig struct {
+	Debug   bool `flag:"debug" default:"true" env:"DEBUG"`
+	Metrics bool `flag:"metrics" default:"true" env:"METRICS"`
+	Tracing bool `flag:"tracing" env:"TRACING"`
+}
+
+func TestReadBoolDefaults(t *testing.T) {
+	cfg, err := readConfig(t, conf.NewReader[boolConfig](), "--metrics=false", "--tracing", "true")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if !cfg.Debug || cfg.Metrics || !cfg.Tracing {
+		t.Errorf("Read() = %+v, want Debug from default, Metrics and Tracing from flags", cfg)
+	}
+}
+
+func TestReadBoolDefaultsEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.env")
+	if err := os.WriteFile(path, []byte("METRICS=false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := readConfig(t, conf.NewReader[boolConfig]().WithFilePath(path))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if !cfg.Debug || cfg.Metrics {
+		t.Errorf("Read() = %+v, want Debug from default and Metrics from file", cfg)
+	}
+}
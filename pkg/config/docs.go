@@ -0,0 +1,299 @@
+package conf
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/defany/platcom/v2/pkg/internal/reflectx"
+)
+
+// FieldDoc describes a single config field by its struct tags.
+type FieldDoc struct {
+	// Path is a dotted path built from Go field names, e.g. "DB.Password".
+	Path string
+	// Type is a Go type of the field.
+	Type string
+	// Flag is a long flag name without dashes.
+	Flag string
+	// ShortFlag is a short flag name without dashes.
+	ShortFlag string
+	// Env is an env variable name with the reader prefix applied.
+	Env string
+	// Default is a raw value of the default tag.
+	Default string
+	// Description is a value of the description tag.
+	Description string
+}
+
+// docSection is a nested config section with its own fields, used to render file examples.
+type docSection struct {
+	field    reflect.StructField
+	fields   []docField
+	sections []docSection
+}
+
+type docField struct {
+	FieldDoc
+	field reflect.StructField
+}
+
+// Fields describes every field of the config in declaration order.
+func (r *Reader[T]) Fields() []FieldDoc {
+	var docs []FieldDoc
+
+	envs := r.envVars()
+
+	_ = walkFields(reflect.New(reflect.TypeOf((*T)(nil)).Elem()), func(path string, field reflect.StructField, _ reflect.Value) error {
+		docs = append(docs, fieldDoc(path, field, envs))
+
+		return nil
+	})
+
+	return docs
+}
+
+// WriteMarkdown writes a Markdown table with every field of the config: its flag, env variable, default and description.
+func (r *Reader[T]) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("| Field | Type | Flag | Env | Default | Description |\n")
+	b.WriteString("|-------|------|------|-----|---------|-------------|\n")
+
+	for _, doc := range r.Fields() {
+		var flags []string
+		if doc.Flag != "" {
+			flags = append(flags, "`--"+doc.Flag+"`")
+		}
+		if doc.ShortFlag != "" {
+			flags = append(flags, "`-"+doc.ShortFlag+"`")
+		}
+
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+			doc.Path,
+			doc.Type,
+			strings.Join(flags, ", "),
+			markdownCode(doc.Env),
+			markdownCode(doc.Default),
+			strings.ReplaceAll(doc.Description, "|", `\|`),
+		)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteYAMLExample writes an example YAML config file filled with default values and commented with descriptions.
+func (r *Reader[T]) WriteYAMLExample(w io.Writer) error {
+	var b strings.Builder
+
+	writeYAMLSection(&b, r.sections("yaml"), 0)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteTOMLExample writes an example TOML config file filled with default values and commented with descriptions.
+func (r *Reader[T]) WriteTOMLExample(w io.Writer) error {
+	var b strings.Builder
+
+	writeTOMLSection(&b, r.sections("toml"), "")
+
+	_, err := io.WriteString(w, strings.TrimLeft(b.String(), "\n"))
+
+	return err
+}
+
+// WriteEnvExample writes a .env.example file with every env variable of the config set to its default value.
+func (r *Reader[T]) WriteEnvExample(w io.Writer) error {
+	var b strings.Builder
+
+	for _, doc := range r.Fields() {
+		if doc.Env == "" {
+			continue
+		}
+
+		if doc.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", doc.Description)
+		}
+
+		fmt.Fprintf(&b, "%s=%s\n", doc.Env, doc.Default)
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// envVars returns env variables of the config by field paths, with the reader prefix applied.
+func (r *Reader[T]) envVars() map[string]string {
+	envs := make(map[string]string)
+	envVars(reflect.TypeOf((*T)(nil)).Elem(), r.envPrefix, "", envs)
+
+	return envs
+}
+
+// fieldDoc describes the field, envs are env variables by field paths, see Reader.envVars.
+func fieldDoc(path string, field reflect.StructField, envs map[string]string) FieldDoc {
+	return FieldDoc{
+		Path:        path,
+		Type:        field.Type.String(),
+		Flag:        field.Tag.Get("flag"),
+		ShortFlag:   field.Tag.Get("s-flag"),
+		Env:         envs[path],
+		Default:     field.Tag.Get("default"),
+		Description: field.Tag.Get("description"),
+	}
+}
+
+// sections returns the sections of the config as the decoder of the format reads them: skipped fields are left out
+// and fields of inlined structs are merged into the parent section, like fileKeys of strict mode sees them.
+func (r *Reader[T]) sections(format string) docSection {
+	s := docSection{}
+	section(&s, reflect.TypeOf((*T)(nil)).Elem(), "", r.envVars(), format)
+
+	return s
+}
+
+func section(s *docSection, t reflect.Type, prefix string, envs map[string]string, format string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if fileKey(f, format) == "-" {
+			continue
+		}
+
+		if embedded, ok := inlinedType(f, format); ok {
+			section(s, embedded, prefix+f.Name+".", envs, format)
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if isNested(f.Type) {
+			nested := f.Type
+			if nested.Kind() == reflect.Ptr {
+				nested = nested.Elem()
+			}
+
+			sub := docSection{field: f}
+			section(&sub, nested, prefix+f.Name+".", envs, format)
+
+			s.sections = append(s.sections, sub)
+
+			continue
+		}
+
+		s.fields = append(s.fields, docField{FieldDoc: fieldDoc(prefix+f.Name, f, envs), field: f})
+	}
+}
+
+func writeYAMLSection(b *strings.Builder, s docSection, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for _, f := range s.fields {
+		if f.Description != "" {
+			fmt.Fprintf(b, "%s# %s\n", indent, f.Description)
+		}
+
+		fmt.Fprintf(b, "%s%s: %s\n", indent, fileKey(f.field, "yaml"), exampleValue(f.field.Type, f.Default))
+	}
+
+	for _, nested := range s.sections {
+		if description := nested.field.Tag.Get("description"); description != "" {
+			fmt.Fprintf(b, "%s# %s\n", indent, description)
+		}
+
+		fmt.Fprintf(b, "%s%s:\n", indent, fileKey(nested.field, "yaml"))
+
+		writeYAMLSection(b, nested, depth+1)
+	}
+}
+
+func writeTOMLSection(b *strings.Builder, s docSection, table string) {
+	for _, f := range s.fields {
+		if f.Description != "" {
+			fmt.Fprintf(b, "# %s\n", f.Description)
+		}
+
+		fmt.Fprintf(b, "%s = %s\n", fileKey(f.field, "toml"), exampleValue(f.field.Type, f.Default))
+	}
+
+	for _, nested := range s.sections {
+		name := fileKey(nested.field, "toml")
+		if table != "" {
+			name = table + "." + name
+		}
+
+		b.WriteString("\n")
+
+		if description := nested.field.Tag.Get("description"); description != "" {
+			fmt.Fprintf(b, "# %s\n", description)
+		}
+
+		fmt.Fprintf(b, "[%s]\n", name)
+
+		writeTOMLSection(b, nested, name)
+	}
+}
+
+// fileKey returns a key of the field in a config file of the format, falling back to the lowercased field name.
+func fileKey(field reflect.StructField, format string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(format), ",")
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
+
+// exampleValue renders raw default value of type t as a YAML/TOML literal.
+func exampleValue(t reflect.Type, raw string) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflectx.DurationType || t == timeType || t.Implements(reflectx.TextUnmarshalerType) || reflect.PointerTo(t).Implements(reflectx.TextUnmarshalerType):
+		return strconv.Quote(raw)
+	case t.Kind() == reflect.String:
+		return strconv.Quote(raw)
+	case t.Kind() == reflect.Bool:
+		if raw == "" {
+			return "false"
+		}
+	case t.Kind() == reflect.Slice:
+		if raw == "" {
+			return "[]"
+		}
+
+		parts := strings.Split(raw, ",")
+		for i, part := range parts {
+			parts[i] = exampleValue(t.Elem(), strings.TrimSpace(part))
+		}
+
+		return "[" + strings.Join(parts, ", ") + "]"
+	case t.Kind() == reflect.Map:
+		return "{}"
+	default:
+		if raw == "" {
+			return "0"
+		}
+	}
+
+	return raw
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + s + "`"
+}
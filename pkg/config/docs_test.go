@@ -0,0 +1,104 @@
+package conf_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conf "github.com/defany/platcom/v2/pkg/config"
+)
+
+type docsConfig struct {
+	MaxConns int `env:"MAX_CONNS,required" default:"10" description:"Max connections"`
+	DB       struct {
+		Host string `env:"HOST" default:"localhost"`
+	} `envPrefix:"DB_"`
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	var b strings.Builder
+
+	if err := conf.NewReader[docsConfig]().WithEnvPrefix("APP").WriteEnvExample(&b); err != nil {
+		t.Fatalf("WriteEnvExample() error = %v", err)
+	}
+
+	want := "# Max connections\nAPP_MAX_CONNS=10\nAPP_DB_HOST=localhost\n"
+	if b.String() != want {
+		t.Errorf("WriteEnvExample() = %q, want %q", b.String(), want)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+
+	if err := conf.NewReader[docsConfig]().WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{"| `MaxConns` | `int` |  | `MAX_CONNS` |", "| `DB.Host` | `string` |  | `DB_HOST` |"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("WriteMarkdown() = %s, want a row %s", b.String(), want)
+		}
+	}
+}
+
+type ExampleBase struct {
+	Name string `yaml:"name" toml:"name" default:"app"`
+}
+
+type exampleConfig struct {
+	ExampleBase `yaml:",inline"`
+
+	Secret string  `yaml:"secret" toml:"-" default:"s3cret"`
+	Ratio  float64 `yaml:"ratio" toml:"ratio" default:"0.5"`
+	DB     struct {
+		Host  string   `yaml:"host" toml:"host" default:"localhost"`
+		Hosts []string `yaml:"hosts" toml:"hosts" default:"a,b"`
+	} `yaml:"db" toml:"db" description:"Database"`
+}
+
+func TestWriteExamplesReadStrict(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		write func(r *conf.Reader[exampleConfig], w io.Writer) error
+		skip  string
+	}{
+		{name: "yaml", file: "config.yaml", write: (*conf.Reader[exampleConfig]).WriteYAMLExample},
+		{name: "toml", file: "config.toml", write: (*conf.Reader[exampleConfig]).WriteTOMLExample, skip: "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+
+			if err := tt.write(conf.NewReader[exampleConfig](), &b); err != nil {
+				t.Fatalf("write example error = %v", err)
+			}
+
+			if strings.Contains(b.String(), "examplebase") {
+				t.Errorf("example = %s, want the inlined struct without its own section", b.String())
+			}
+
+			if tt.skip != "" && strings.Contains(b.String(), tt.skip) {
+				t.Errorf("example = %s, want no skipped key %q", b.String(), tt.skip)
+			}
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := readConfig(t, conf.NewReader[exampleConfig]().WithFilePath(path).WithStrict())
+			if err != nil {
+				t.Fatalf("Read() of example\n%s\nerror = %v", b.String(), err)
+			}
+
+			if cfg.Name != "app" || cfg.Ratio != 0.5 || cfg.DB.Host != "localhost" || len(cfg.DB.Hosts) != 2 {
+				t.Errorf("Read() = %+v, want the defaults of the example", cfg)
+			}
+		})
+	}
+}
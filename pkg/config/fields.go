@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldFunc is called by walkFields for every leaf field of a config struct.
+// path is a dotted path built from Go field names, e.g. "DB.Password".
+type fieldFunc func(path string, field reflect.StructField, value reflect.Value) error
+
+// walkFields visits every exported leaf field of the struct v points to,
+// descending into nested structs and non-nil pointers to structs.
+func walkFields(v reflect.Value, fn fieldFunc) error {
+	return walkStruct(reflect.Indirect(v), "", fn)
+}
+
+func walkStruct(v reflect.Value, prefix string, fn fieldFunc) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		path := prefix + field.Name
+
+		if isNested(field.Type) {
+			if value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					continue
+				}
+
+				value = value.Elem()
+			}
+
+			if err := walkStruct(value, path+".", fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := fn(path, field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isNested reports whether a field of type t holds a nested config section
+// rather than a single value.
+func isNested(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t != timeType
+}
@@ -0,0 +1,96 @@
+package conf
+
+import (
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+
+	gocfg "github.com/dsbasko/go-cfg"
+)
+
+// FileFinder describes where Reader.WithFileFinderConfig looks for the config path.
+type FileFinder struct {
+	// Flag is a long flag name, e.g. "config" for --config.
+	Flag string
+	// ShortFlag is a short flag name, e.g. "c" for -c.
+	ShortFlag string
+	// Env is an env variable name, prefixed if Reader.WithEnvPrefix is used.
+	Env string
+}
+
+// DefaultFileFinder is used by Reader.WithFileFinder.
+var DefaultFileFinder = FileFinder{
+	Flag:      "config",
+	ShortFlag: "c",
+	Env:       "CONFIG_FILE_PATH",
+}
+
+/*
+WithFileFinder - will try to find config path in flags passed to application and in environment variables
+
+To pass a config file use flags like: -c="some very cool path to config" --config="some very cool path with long flag" or use CONFIG_FILE_PATH env variable
+*/
+func (r *Reader[T]) WithFileFinder() error {
+	return r.WithFileFinderConfig(DefaultFileFinder)
+}
+
+// WithFileFinderConfig works like WithFileFinder, but looks for the config path in flags and env variable named by finder.
+// Env variable wins over flags.
+func (r *Reader[T]) WithFileFinderConfig(finder FileFinder) error {
+	r.logger.Info("finding config file...")
+
+	var path string
+
+	if finder.Flag != "" || finder.ShortFlag != "" {
+		ff := reflect.New(fileFinderType(finder))
+
+		if err := gocfg.ReadFlag(ff.Interface()); err != nil {
+			return err
+		}
+
+		path = ff.Elem().Field(0).String()
+	}
+
+	if finder.Env != "" {
+		r.finderEnv = r.envPrefix + finder.Env
+
+		if value, ok := os.LookupEnv(r.finderEnv); ok && value != "" {
+			path = value
+		}
+	}
+
+	if path == "" {
+		return ErrConfigPathEmpty
+	}
+
+	r.logger.Info("config file found", slog.String("path", path))
+
+	r.WithFilePath(path)
+
+	return nil
+}
+
+// fileFinderType builds a struct type with flag tags of finder, so the flags are registered in go-cfg
+// and are not rejected as unknown while reading the config itself.
+func fileFinderType(finder FileFinder) reflect.Type {
+	var tag strings.Builder
+
+	if finder.ShortFlag != "" {
+		tag.WriteString(`s-flag:"` + finder.ShortFlag + `" `)
+	}
+
+	if finder.Flag != "" {
+		tag.WriteString(`flag:"` + finder.Flag + `" `)
+	}
+
+	tag.WriteString(`description:"path to config file of app"`)
+
+	return reflect.StructOf([]reflect.StructField{
+		{
+			Name: "Path",
+			Type: reflect.TypeOf(""),
+			Tag:  reflect.StructTag(tag.String()),
+		},
+	})
+}
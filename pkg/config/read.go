@@ -1,31 +1,45 @@
 package conf
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 
+	"github.com/caarlos0/env/v10"
 	gocfg "github.com/dsbasko/go-cfg"
+	"github.com/joho/godotenv"
 )
 
 var (
 	ErrConfigPathEmpty = errors.New("config path is empty")
 )
 
-type fileFinder struct {
-	Path string `s-flag:"c" flag:"config" env:"CONFIG_FILE_PATH" description:"path to config file of app"`
-}
-
 type Reader[T any] struct {
 	filePath string
 
+	envPrefix string
+	finderEnv string
+
+	resolvers map[string]Resolver
+
+	sources []prioritizedSource
+
+	strict            bool
+	strictEnvPrefixes []string
+
 	logger *slog.Logger
 }
 
 // NewReader creates a new structure with a type of your config.
 func NewReader[T any]() *Reader[T] {
 	return &Reader[T]{
-		logger: slog.Default(),
+		resolvers: defaultResolvers(),
+		logger:    slog.Default(),
 	}
 }
 
@@ -43,29 +57,18 @@ func (r *Reader[T]) WithFilePath(path string) *Reader[T] {
 }
 
 /*
-WithFileFinder - will try to find config path in flags passed to application and in environment variables
+WithEnvPrefix namespaces every env lookup of the reader, e.g. with prefix "BILLING" field tagged `env:"DB_HOST"` is read from BILLING_DB_HOST
 
-To pass a config file use flags like: -c="some very cool path to config" --config="some very cool path with long flag" or use CONFIG_FILE_PATH env variable
+Call it before Reader.WithFileFinder, so the config path env variable gets the prefix as well
 */
-func (r *Reader[T]) WithFileFinder() error {
-	r.logger.Info("finding config file...")
-
-	fr := NewReader[fileFinder]()
-
-	fr.WithLogger(r.logger)
-
-	ff, err := fr.Read()
-	if err != nil {
-		return err
+func (r *Reader[T]) WithEnvPrefix(prefix string) *Reader[T] {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
 	}
 
-	if ff.Path == "" {
-		return ErrConfigPathEmpty
-	}
+	r.envPrefix = prefix
 
-	r.WithFilePath(ff.Path)
-
-	return nil
+	return r
 }
 
 /*
@@ -73,38 +76,95 @@ Read - this method reads environment variables, flags and file by path (to pass
 
 # Firstly
 
-	Reader will check flags
+	Reader will set values from `default:"..."` tags
 
 # Secondly
 
-	Reader will check file if its exists
+	Reader will check flags
 
 # Thirdly
 
-	Reader will check env variables
+	Reader will check file if its exists
+
+# Fourthly
+
+	Reader will check sources in order of priority (to add use method Reader.WithSource)
+
+# Fifthly
+
+	Reader will check env variables (prefixed if Reader.WithEnvPrefix is used)
+
+# Finally
+
+	Reader will expand references like ${file:/run/secrets/db_pass} or ${env:OTHER_VAR} in string fields and pointers to them (to add your own schemes use method Reader.WithResolver, to keep ${ as is write $${)
 */
 func (r *Reader[T]) Read() (T, error) {
+	return r.read(func(cfg *T) error {
+		return r.readSources(context.Background(), cfg)
+	})
+}
+
+// read reads the config like Read does, applying values of sources with readSources.
+func (r *Reader[T]) read(readSources func(cfg *T) error) (T, error) {
 	cfg := new(T)
 
+	r.logger.Info("setting default values...")
+
+	if err := applyDefaults(cfg); err != nil {
+		return *cfg, err
+	}
+
 	r.logger.Info("reading flags...")
 
-	if err := gocfg.ReadFlag(cfg); err != nil {
+	readFlag := func() error { return gocfg.ReadFlag(cfg) }
+	hasFlag := func(field reflect.StructField) bool { return flagPassed(os.Args[1:], field) }
+
+	if err := keepBools(cfg, readFlag, hasFlag); err != nil {
 		return *cfg, err
 	}
 
 	if r.filePath != "" {
 		r.logger.Info("reading config file...", slog.String("path", r.filePath))
 
-		if err := gocfg.ReadFile(r.filePath, cfg); err != nil {
+		readFile := func() error { return gocfg.ReadFile(r.filePath, cfg) }
+
+		hasKey, err := r.fileHasKey()
+		if err != nil {
+			return *cfg, err
+		}
+
+		if err := keepBools(cfg, readFile, hasKey); err != nil {
 			return *cfg, err
 		}
+
+		if r.strict {
+			if err := r.checkFileKeys(); err != nil {
+				return *cfg, err
+			}
+		}
 	} else {
 		r.logger.Info("config file is missing, skipping...")
 	}
 
-	r.logger.Info("reading env variables...")
+	if err := readSources(cfg); err != nil {
+		return *cfg, err
+	}
+
+	r.logger.Info("reading env variables...", slog.String("prefix", r.envPrefix))
+
+	if err := env.ParseWithOptions(cfg, env.Options{Prefix: r.envPrefix}); err != nil {
+		return *cfg, fmt.Errorf("failed to parse env: %w", err)
+	}
 
-	if err := gocfg.ReadEnv(cfg); err != nil {
+	if r.strict {
+		if err := r.checkEnv(); err != nil {
+			return *cfg, err
+		}
+	}
+
+	r.logger.Info("resolving references...")
+
+	if err := r.resolve(cfg); err != nil {
 		return *cfg, err
 	}
 
@@ -112,3 +172,22 @@ func (r *Reader[T]) Read() (T, error) {
 
 	return *cfg, nil
 }
+
+// fileHasKey returns a func reporting whether the config file has a value for a field.
+// Only .env files are read by go-cfg field by field, decoders of other formats leave missing fields as they are.
+func (r *Reader[T]) fileHasKey() (func(field reflect.StructField) bool, error) {
+	if !strings.EqualFold(filepath.Ext(r.filePath), ".env") {
+		return func(reflect.StructField) bool { return true }, nil
+	}
+
+	env, err := godotenv.Read(r.filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(field reflect.StructField) bool {
+		_, ok := env[field.Tag.Get("env")]
+
+		return ok
+	}, nil
+}
@@ -0,0 +1,153 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownScheme = errors.New("unknown reference scheme")
+	ErrEnvNotSet     = errors.New("env variable is not set")
+)
+
+// refPattern matches references like ${file:/run/secrets/db_pass} or ${env:OTHER_VAR}, and escaped ones like $${env:OTHER_VAR}.
+var refPattern = regexp.MustCompile(`\$(\$?)\{([a-zA-Z][a-zA-Z0-9+.-]*):([^}]*)\}`)
+
+// Resolver turns the reference part of ${scheme:ref} into a value.
+type Resolver interface {
+	Resolve(ref string) (string, error)
+}
+
+// ResolverFunc is an adapter to allow the use of ordinary functions as a Resolver.
+type ResolverFunc func(ref string) (string, error)
+
+func (f ResolverFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// FileResolver reads a value from the file by path, e.g. a secret mounted by Kubernetes.
+// Trailing line breaks are trimmed.
+type FileResolver struct{}
+
+func (FileResolver) Resolve(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EnvResolver reads a value from the environment variable.
+type EnvResolver struct{}
+
+func (EnvResolver) Resolve(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrEnvNotSet, name)
+	}
+
+	return value, nil
+}
+
+func defaultResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		"file": FileResolver{},
+		"env":  EnvResolver{},
+	}
+}
+
+// WithResolver registers a resolver for references with the given scheme, e.g. ${vault:db/password}.
+// It replaces the resolver previously registered for the scheme, including the built-in file and env ones.
+func (r *Reader[T]) WithResolver(scheme string, resolver Resolver) *Reader[T] {
+	r.resolvers[scheme] = resolver
+
+	return r
+}
+
+/*
+resolve expands references in string fields, non-nil pointers to strings, string slices and string maps of cfg
+
+A reference escaped with one more dollar sign is kept as a literal, e.g. $${env:HOME} becomes ${env:HOME}.
+*/
+func (r *Reader[T]) resolve(cfg *T) error {
+	return walkFields(reflect.ValueOf(cfg), func(path string, _ reflect.StructField, value reflect.Value) error {
+		if value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.String {
+			value = value.Elem()
+		}
+
+		switch {
+		case value.Kind() == reflect.String:
+			resolved, err := r.expand(value.String())
+			if err != nil {
+				return fmt.Errorf("failed to resolve field %s: %w", path, err)
+			}
+
+			value.SetString(resolved)
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < value.Len(); i++ {
+				resolved, err := r.expand(value.Index(i).String())
+				if err != nil {
+					return fmt.Errorf("failed to resolve field %s[%d]: %w", path, i, err)
+				}
+
+				value.Index(i).SetString(resolved)
+			}
+		case value.Kind() == reflect.Map && value.Type().Elem().Kind() == reflect.String:
+			iter := value.MapRange()
+			for iter.Next() {
+				resolved, err := r.expand(iter.Value().String())
+				if err != nil {
+					return fmt.Errorf("failed to resolve field %s[%v]: %w", path, iter.Key(), err)
+				}
+
+				value.SetMapIndex(iter.Key(), reflect.ValueOf(resolved).Convert(value.Type().Elem()))
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *Reader[T]) expand(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var resolveErr error
+
+	expanded := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if resolveErr != nil {
+			return ref
+		}
+
+		parts := refPattern.FindStringSubmatch(ref)
+		escaped, scheme, target := parts[1], parts[2], parts[3]
+
+		if escaped != "" {
+			return ref[1:]
+		}
+
+		resolver, ok := r.resolvers[scheme]
+		if !ok {
+			resolveErr = fmt.Errorf("%w: %s", ErrUnknownScheme, scheme)
+
+			return ref
+		}
+
+		value, err := resolver.Resolve(target)
+		if err != nil {
+			resolveErr = fmt.Errorf("%s: %w", scheme, err)
+
+			return ref
+		}
+
+		return value
+	})
+
+	return expanded, resolveErr
+}
@@ -0,0 +1,92 @@
+package conf_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conf "github.com/defany/platcom/v2/pkg/config"
+)
+
+type resolveConfig struct {
+	Password string   `env:"PASSWORD"`
+	Token    *string  `env:"TOKEN"`
+	Hosts    []string `env:"HOSTS"`
+	Labels   map[string]string
+	Template string `env:"TEMPLATE"`
+}
+
+func TestReadResolve(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "db_pass")
+	if err := os.WriteFile(secret, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("RESOLVE_PASSWORD", "${file:"+secret+"}")
+	t.Setenv("RESOLVE_TOKEN", "${vault:api/token}")
+	t.Setenv("RESOLVE_HOSTS", "${env:RESOLVE_HOST},backup")
+	t.Setenv("RESOLVE_HOST", "primary")
+	t.Setenv("RESOLVE_TEAM", "billing")
+	t.Setenv("RESOLVE_TEMPLATE", "home is $${env:HOME}")
+
+	vault := conf.ResolverFunc(func(ref string) (string, error) {
+		return "token of " + ref, nil
+	})
+
+	labels := conf.NewMemorySource("labels", map[string]any{
+		"labels": map[string]any{"team": "${env:RESOLVE_TEAM}"},
+	})
+
+	r := conf.NewReader[resolveConfig]().WithEnvPrefix("RESOLVE").WithSource(labels, 0).WithResolver("vault", vault)
+
+	cfg, err := readConfig(t, r)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if cfg.Password != "s3cret" {
+		t.Errorf("Password = %q, want the content of the file without the line break", cfg.Password)
+	}
+
+	if cfg.Token == nil || *cfg.Token != "token of api/token" {
+		t.Errorf("Token = %v, want resolved by the custom resolver", cfg.Token)
+	}
+
+	if len(cfg.Hosts) != 2 || cfg.Hosts[0] != "primary" {
+		t.Errorf("Hosts = %v, want [primary backup]", cfg.Hosts)
+	}
+
+	if cfg.Labels["team"] != "billing" {
+		t.Errorf("Labels = %v, want team billing", cfg.Labels)
+	}
+
+	if cfg.Template != "home is ${env:HOME}" {
+		t.Errorf("Template = %q, want the escaped reference kept as is", cfg.Template)
+	}
+}
+
+func TestReadResolveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		sentinel error
+		want     string
+	}{
+		{name: "unknown scheme", value: "${vault:db/password}", sentinel: conf.ErrUnknownScheme, want: "field Password"},
+		{name: "env not set", value: "${env:RESOLVE_MISSING}", sentinel: conf.ErrEnvNotSet, want: "field Password"},
+		{name: "missing file", value: "${file:/nonexistent/secret}", sentinel: os.ErrNotExist, want: "field Password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RESOLVE_PASSWORD", tt.value)
+
+			_, err := readConfig(t, conf.NewReader[resolveConfig]().WithEnvPrefix("RESOLVE"))
+			if !errors.Is(err, tt.sentinel) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want %v naming %s", err, tt.sentinel, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/internal/reflectx"
+)
+
+// Source is an extra layer of config values beyond flags, file and env, e.g. a key-value store.
+//
+// Load returns values keyed like in a config file: nested maps for sections or dotted keys like "db.host".
+// Keys are matched with yaml, json and toml tags or field names case-insensitively.
+type Source interface {
+	Name() string
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+type prioritizedSource struct {
+	Source
+	priority int
+}
+
+// WithSource adds a source of config values.
+// Sources are applied after the config file and before env variables, in order of priority: the higher one wins.
+// Keys of sources matching no field are rejected with ErrUnknownKey if the reader is strict, see Reader.WithStrict.
+func (r *Reader[T]) WithSource(source Source, priority int) *Reader[T] {
+	r.sources = append(r.sources, prioritizedSource{Source: source, priority: priority})
+
+	sort.SliceStable(r.sources, func(i, j int) bool {
+		return r.sources[i].priority < r.sources[j].priority
+	})
+
+	return r
+}
+
+/*
+Watch polls sources every interval and calls onChange with the freshly read config each time the values of any source change
+
+It blocks until ctx is done, which cancels a poll in progress as well. Failed reads are logged and retried on the next tick.
+Values are compared with the last ones a config was read with, the first successful read only remembers them
+*/
+func (r *Reader[T]) Watch(ctx context.Context, interval time.Duration, onChange func(cfg T)) {
+	var (
+		last   []map[string]any
+		seeded bool
+	)
+
+	poll := func() {
+		values, err := r.loadSources(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			r.logger.Error("failed to poll config sources", slog.String("error", err.Error()))
+
+			return
+		}
+
+		if seeded && reflect.DeepEqual(last, values) {
+			return
+		}
+
+		cfg, err := r.read(func(cfg *T) error { return r.applySources(cfg, values) })
+		if err != nil {
+			r.logger.Error("failed to read changed config", slog.String("error", err.Error()))
+
+			return
+		}
+
+		changed := seeded
+		last, seeded = values, true
+
+		if changed {
+			onChange(cfg)
+		}
+	}
+
+	poll()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+func (r *Reader[T]) loadSources(ctx context.Context) ([]map[string]any, error) {
+	values := make([]map[string]any, 0, len(r.sources))
+
+	for _, source := range r.sources {
+		v, err := source.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load source %s: %w", source.Name(), err)
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// readSources loads every source and applies the values to cfg.
+func (r *Reader[T]) readSources(ctx context.Context, cfg *T) error {
+	values, err := r.loadSources(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.applySources(cfg, values)
+}
+
+// applySources applies values loaded by loadSources to cfg, in order of sources.
+func (r *Reader[T]) applySources(cfg *T, values []map[string]any) error {
+	for i, source := range r.sources {
+		r.logger.Info("reading config source...", slog.String("source", source.Name()), slog.Int("priority", source.priority))
+
+		var unknown *[]error
+		if r.strict {
+			unknown = new([]error)
+		}
+
+		if err := decodeMap(reflect.ValueOf(cfg).Elem(), values[i], "", unknown); err != nil {
+			return fmt.Errorf("failed to decode source %s: %w", source.Name(), err)
+		}
+
+		if unknown != nil && len(*unknown) > 0 {
+			return fmt.Errorf("failed to decode source %s: %w", source.Name(), errors.Join(*unknown...))
+		}
+	}
+
+	return nil
+}
+
+// decodeMap sets fields of struct v from values keyed like in a config file.
+// Keys matching no field are skipped, or collected into unknown if it isn't nil.
+func decodeMap(v reflect.Value, values map[string]any, prefix string, unknown *[]error) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+
+		if head, tail, ok := strings.Cut(key, "."); ok {
+			key, value = head, map[string]any{tail: value}
+		}
+
+		field, ok := fieldByKey(v, key)
+		if !ok {
+			if unknown != nil {
+				*unknown = append(*unknown, unknownError(ErrUnknownKey, prefix+key, suggest(key, sourceKeys(v.Type()))))
+			}
+
+			continue
+		}
+
+		if err := decodeValue(field, value, prefix+key, unknown); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeValue(field reflect.Value, value any, path string, unknown *[]error) error {
+	if value == nil {
+		return nil
+	}
+
+	if isNested(field.Type()) {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("field %s: expected section, got %T", path, value)
+		}
+
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+
+			field = field.Elem()
+		}
+
+		return decodeMap(field, nested, path+".", unknown)
+	}
+
+	switch typed := value.(type) {
+	case []any:
+		if field.Kind() != reflect.Slice {
+			return fmt.Errorf("field %s: expected %s, got list", path, field.Type())
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(typed), len(typed))
+
+		for i, item := range typed {
+			if err := decodeValue(slice.Index(i), item, fmt.Sprintf("%s[%d]", path, i), unknown); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+	case map[string]any:
+		if field.Kind() != reflect.Map || field.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("field %s: expected %s, got map", path, field.Type())
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+
+		for key, item := range typed {
+			elem := reflect.New(field.Type().Elem()).Elem()
+
+			if err := decodeValue(elem, item, path+"."+key, unknown); err != nil {
+				return err
+			}
+
+			field.SetMapIndex(reflect.ValueOf(key).Convert(field.Type().Key()), elem)
+		}
+	default:
+		if err := reflectx.SetValue(field, scalarOf(value)); err != nil {
+			return fmt.Errorf("field %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// scalarOf formats a scalar value of a source for reflectx.SetValue.
+// Floats are formatted without an exponent, since JSON decodes every number to float64, e.g. 1000000 fits an int field.
+func scalarOf(value any) string {
+	switch typed := value.(type) {
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(typed), 'f', -1, 32)
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return typed.String()
+		}
+
+		if f, err := typed.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+
+		return typed.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// fieldByKey finds an exported field of struct v by a config file key.
+func fieldByKey(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, format := range []string{"yaml", "json", "toml"} {
+			if name := fileKey(field, format); name != "-" && strings.EqualFold(name, key) {
+				return v.Field(i), true
+			}
+		}
+
+		if strings.EqualFold(field.Name, key) {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// sourceKeys returns the keys fieldByKey matches fields of struct t by, for suggestions.
+func sourceKeys(t reflect.Type) []string {
+	var keys []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := field.Name
+
+		for _, format := range []string{"yaml", "json", "toml"} {
+			if name := fileKey(field, format); name != "-" && name != "" {
+				key = name
+
+				break
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,260 @@
+package conf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	conf "github.com/defany/platcom/v2/pkg/config"
+)
+
+type sourceConfig struct {
+	MaxConns int           `yaml:"max_conns"`
+	Ratio    float64       `yaml:"ratio"`
+	Timeout  time.Duration `yaml:"timeout"`
+	DB       struct {
+		Host string `yaml:"host"`
+		Port uint16 `yaml:"port"`
+	} `yaml:"db"`
+	Tags []string `yaml:"tags"`
+	ID   int64    `yaml:"id"`
+}
+
+// readConfig reads the config with args instead of flags of the test binary, which go-cfg doesn't know.
+func readConfig[T any](t *testing.T, r *conf.Reader[T], args ...string) (T, error) {
+	t.Helper()
+
+	return quiet(t, r, args...).Read()
+}
+
+// quiet makes the reader see args instead of flags of the test binary and discard its logs.
+func quiet[T any](t *testing.T, r *conf.Reader[T], args ...string) *conf.Reader[T] {
+	t.Helper()
+
+	osArgs := os.Args
+	os.Args = append([]string{osArgs[0]}, args...)
+	t.Cleanup(func() { os.Args = osArgs })
+
+	return r.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestReadSources(t *testing.T) {
+	low := conf.NewMemorySource("low", map[string]any{
+		"max_conns": 10.0,
+		"db":        map[string]any{"host": "low", "port": 5432.0},
+	})
+
+	high := conf.NewMemorySource("high", map[string]any{
+		"max_conns": 1000000.0,
+		"ratio":     0.25,
+		"timeout":   "5s",
+		"db.host":   "high",
+		"tags":      []any{"a", "b"},
+	})
+
+	cfg, err := readConfig(t, conf.NewReader[sourceConfig]().WithSource(high, 2).WithSource(low, 1))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if cfg.MaxConns != 1000000 {
+		t.Errorf("MaxConns = %d, want 1000000", cfg.MaxConns)
+	}
+
+	if cfg.Ratio != 0.25 {
+		t.Errorf("Ratio = %v, want 0.25", cfg.Ratio)
+	}
+
+	if cfg.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", cfg.Timeout)
+	}
+
+	if cfg.DB.Host != "high" || cfg.DB.Port != 5432 {
+		t.Errorf("DB = %+v, want high:5432", cfg.DB)
+	}
+
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "a" || cfg.Tags[1] != "b" {
+		t.Errorf("Tags = %v, want [a b]", cfg.Tags)
+	}
+}
+
+func TestReadSourcesInvalidValue(t *testing.T) {
+	src := conf.NewMemorySource("bad", map[string]any{"max_conns": 1.5})
+
+	if _, err := readConfig(t, conf.NewReader[sourceConfig]().WithSource(src, 0)); err == nil {
+		t.Fatal("Read() error = nil, want error for a fractional int")
+	}
+}
+
+func TestReadSourcesStrict(t *testing.T) {
+	src := conf.NewMemorySource("typo", map[string]any{
+		"max_con": 10.0,
+		"db":      map[string]any{"hots": "localhost"},
+	})
+
+	_, err := readConfig(t, conf.NewReader[sourceConfig]().WithSource(src, 0).WithStrict())
+	if !errors.Is(err, conf.ErrUnknownKey) {
+		t.Fatalf("Read() error = %v, want conf.ErrUnknownKey", err)
+	}
+
+	for _, want := range []string{`"db.hots", did you mean "host"?`, `"max_con", did you mean "max_conns"?`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Read() error = %v, want it to mention %s", err, want)
+		}
+	}
+
+	if _, err := readConfig(t, conf.NewReader[sourceConfig]().WithSource(src, 0)); err != nil {
+		t.Errorf("Read() without strict error = %v, want nil", err)
+	}
+}
+
+func TestReadHTTPSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"max_conns": 1e6, "ratio": 0.5, "id": 9007199254740993}`)
+	}))
+	defer srv.Close()
+
+	cfg, err := readConfig(t, conf.NewReader[sourceConfig]().WithSource(conf.NewHTTPSource(srv.URL), 0))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if cfg.MaxConns != 1000000 || cfg.Ratio != 0.5 {
+		t.Errorf("MaxConns, Ratio = %d, %v, want 1000000, 0.5", cfg.MaxConns, cfg.Ratio)
+	}
+
+	if cfg.ID != 9007199254740993 {
+		t.Errorf("ID = %d, want 9007199254740993", cfg.ID)
+	}
+}
+
+// scriptedSource returns values or an error set by a test and counts loads.
+type scriptedSource struct {
+	mu     sync.Mutex
+	values map[string]any
+	err    error
+	loads  int
+	block  bool
+}
+
+func (s *scriptedSource) Name() string {
+	return "scripted"
+}
+
+func (s *scriptedSource) Load(ctx context.Context) (map[string]any, error) {
+	s.mu.Lock()
+	s.loads++
+	block := s.block
+	values, err := s.values, s.err
+	s.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	return values, err
+}
+
+func (s *scriptedSource) set(values map[string]any, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values, s.err = values, err
+}
+
+// waitLoads waits until the source is loaded n more times.
+func (s *scriptedSource) waitLoads(t *testing.T, n int) {
+	t.Helper()
+
+	s.mu.Lock()
+	want := s.loads + n
+	s.mu.Unlock()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		s.mu.Lock()
+		loads := s.loads
+		s.mu.Unlock()
+
+		if loads >= want {
+			return
+		}
+	}
+
+	t.Fatalf("source isn't loaded %d more times", n)
+}
+
+func TestWatch(t *testing.T) {
+	src := &scriptedSource{err: errors.New("unavailable")}
+
+	r := quiet(t, conf.NewReader[sourceConfig]().WithSource(src, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan sourceConfig, 10)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		r.Watch(ctx, time.Millisecond, func(cfg sourceConfig) { changes <- cfg })
+	}()
+
+	src.waitLoads(t, 2)
+	src.set(map[string]any{"max_conns": 1.0}, nil)
+	src.waitLoads(t, 3)
+
+	select {
+	case cfg := <-changes:
+		t.Fatalf("onChange(%+v) after the first successful read, want it only to remember values", cfg)
+	default:
+	}
+
+	src.set(map[string]any{"max_conns": 2.0}, nil)
+
+	select {
+	case cfg := <-changes:
+		if cfg.MaxConns != 2 {
+			t.Errorf("onChange() MaxConns = %d, want 2", cfg.MaxConns)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onChange() isn't called after values changed")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWatchCancelLoad(t *testing.T) {
+	src := &scriptedSource{block: true}
+
+	r := quiet(t, conf.NewReader[sourceConfig]().WithSource(src, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		r.Watch(ctx, time.Millisecond, func(sourceConfig) { t.Error("onChange() called for a canceled load") })
+	}()
+
+	src.waitLoads(t, 1)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch() doesn't return after ctx is canceled during a load")
+	}
+}
@@ -0,0 +1,179 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirSource reads values from a directory of files, like a Kubernetes ConfigMap mounted as a volume.
+// Every file is a key named after the file, e.g. file "db.host" sets key "db.host", and its content is the value.
+type DirSource struct {
+	dir string
+}
+
+func NewDirSource(dir string) *DirSource {
+	return &DirSource{dir: dir}
+}
+
+func (s *DirSource) Name() string {
+	return "dir:" + s.dir
+}
+
+func (s *DirSource) Load(_ context.Context) (map[string]any, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(entries))
+
+	for _, entry := range entries {
+		// Kubernetes keeps its own bookkeeping in hidden entries like ..data
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		values[entry.Name()] = strings.TrimRight(string(content), "\r\n")
+	}
+
+	return values, nil
+}
+
+// HTTPSource reads values from an HTTP endpoint responding with a JSON object.
+type HTTPSource struct {
+	url     string
+	client  *http.Client
+	headers http.Header
+}
+
+func NewHTTPSource(url string) *HTTPSource {
+	return &HTTPSource{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		headers: make(http.Header),
+	}
+}
+
+func (s *HTTPSource) WithClient(client *http.Client) *HTTPSource {
+	s.client = client
+
+	return s
+}
+
+// WithHeader sets a header sent with every request, e.g. an authorization token.
+func (s *HTTPSource) WithHeader(key, value string) *HTTPSource {
+	s.headers.Set(key, value)
+
+	return s
+}
+
+func (s *HTTPSource) Name() string {
+	return "http:" + s.url
+}
+
+func (s *HTTPSource) Load(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = s.headers.Clone()
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var values map[string]any
+
+	// numbers are kept as written, so big integers don't lose precision in float64
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// MemorySource keeps values in memory. It is safe for concurrent use and is handy in tests.
+type MemorySource struct {
+	mu     sync.RWMutex
+	name   string
+	values map[string]any
+}
+
+func NewMemorySource(name string, values map[string]any) *MemorySource {
+	s := &MemorySource{
+		name:   name,
+		values: make(map[string]any, len(values)),
+	}
+
+	for key, value := range values {
+		s.values[key] = value
+	}
+
+	return s
+}
+
+// Set sets a value by key, e.g. "db.host".
+func (s *MemorySource) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+}
+
+// Delete removes a value by key.
+func (s *MemorySource) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
+
+func (s *MemorySource) Name() string {
+	return "memory:" + s.name
+}
+
+func (s *MemorySource) Load(_ context.Context) (map[string]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := make(map[string]any, len(s.values))
+	for key, value := range s.values {
+		values[key] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,380 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownKey    = errors.New("unknown config key")
+	ErrUnknownEnvVar = errors.New("unknown env variable")
+)
+
+// WithStrict makes Read fail on keys in the config file that do not match any field of the config.
+//
+// Env variables starting with any of envPrefixes (e.g. "BILLING_") that no field is bound to are rejected as well.
+func (r *Reader[T]) WithStrict(envPrefixes ...string) *Reader[T] {
+	r.strict = true
+	r.strictEnvPrefixes = append(r.strictEnvPrefixes, envPrefixes...)
+
+	return r
+}
+
+// checkFileKeys reports keys of the config file that would be silently ignored by the decoder.
+func (r *Reader[T]) checkFileKeys() error {
+	content, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return err
+	}
+
+	var (
+		data   map[string]any
+		format string
+	)
+
+	switch strings.ToLower(filepath.Ext(r.filePath)) {
+	case ".json":
+		format = "json"
+		err = json.Unmarshal(content, &data)
+	case ".yaml", ".yml":
+		format = "yaml"
+		err = yaml.Unmarshal(content, &data)
+	case ".toml":
+		format = "toml"
+		err = toml.Unmarshal(content, &data)
+	case ".env":
+		env, envErr := godotenv.UnmarshalBytes(content)
+		if envErr != nil {
+			return envErr
+		}
+
+		return checkEnvNames(env, envNames[T](""), ErrUnknownKey)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var unknown []error
+
+	collectUnknownKeys(data, reflect.TypeOf((*T)(nil)).Elem(), format, "", &unknown)
+
+	return errors.Join(unknown...)
+}
+
+// checkEnv reports env variables under the strict prefixes and the env prefix of the reader that are not bound to any field.
+func (r *Reader[T]) checkEnv() error {
+	prefixes := r.strictEnvPrefixes
+	if r.envPrefix != "" {
+		prefixes = append(prefixes[:len(prefixes):len(prefixes)], r.envPrefix)
+	}
+
+	if len(prefixes) == 0 {
+		return nil
+	}
+
+	env := make(map[string]string)
+
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				env[name] = value
+
+				break
+			}
+		}
+	}
+
+	known := envNames[T](r.envPrefix)
+	if r.finderEnv != "" {
+		known = append(known, r.finderEnv)
+	}
+
+	return checkEnvNames(env, known, ErrUnknownEnvVar)
+}
+
+// envNames returns names of env variables bound to fields of T.
+func envNames[T any](prefix string) []string {
+	vars := make(map[string]string)
+	envVars(reflect.TypeOf((*T)(nil)).Elem(), prefix, "", vars)
+
+	names := make([]string, 0, len(vars))
+	for _, name := range vars {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+envVars collects names of env variables bound to fields of struct t by dotted paths of Go field names, like walkFields builds
+
+Names are resolved the way env.Parse does: options like ",required" are cut from the env tag
+and envPrefix tags of nested structs are prepended to names of their fields.
+*/
+func envVars(t reflect.Type, prefix, path string, vars map[string]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if isNested(field.Type) {
+			nested := field.Type
+			if nested.Kind() == reflect.Ptr {
+				nested = nested.Elem()
+			}
+
+			envVars(nested, prefix+field.Tag.Get("envPrefix"), path+field.Name+".", vars)
+
+			continue
+		}
+
+		if name, _, _ := strings.Cut(field.Tag.Get("env"), ","); name != "" {
+			vars[path+field.Name] = prefix + name
+		}
+	}
+}
+
+func checkEnvNames(env map[string]string, known []string, sentinel error) error {
+	knownSet := make(map[string]struct{}, len(known))
+	for _, name := range known {
+		knownSet[name] = struct{}{}
+	}
+
+	var unknown []error
+
+	for name := range env {
+		if _, ok := knownSet[name]; ok {
+			continue
+		}
+
+		unknown = append(unknown, unknownError(sentinel, name, suggest(name, known)))
+	}
+
+	sortErrors(unknown)
+
+	return errors.Join(unknown...)
+}
+
+func collectUnknownKeys(data map[string]any, t reflect.Type, format, prefix string, unknown *[]error) {
+	keys := fileKeys(t, format)
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+
+	found := make([]string, 0, len(data))
+	for key := range data {
+		found = append(found, key)
+	}
+
+	sort.Strings(found)
+
+	for _, key := range found {
+		fieldType, ok := lookupKey(keys, key, format)
+		if !ok {
+			*unknown = append(*unknown, unknownError(ErrUnknownKey, prefix+key, suggest(key, names)))
+
+			continue
+		}
+
+		collectNested(data[key], fieldType, format, prefix+key, unknown)
+	}
+}
+
+func collectNested(value any, t reflect.Type, format, path string, unknown *[]error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch nested := value.(type) {
+	case map[string]any:
+		if isNested(t) {
+			collectUnknownKeys(nested, t, format, path+".", unknown)
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for i, item := range nested {
+			collectNested(item, t.Elem(), format, fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	case []map[string]any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+
+		for i, item := range nested {
+			collectNested(item, t.Elem(), format, fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
+
+// fileKeys returns the keys a decoder of the format accepts for struct t, mapped to the field types.
+func fileKeys(t reflect.Type, format string) map[string]reflect.Type {
+	keys := make(map[string]reflect.Type)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get(format), ",")
+		if name == "-" {
+			continue
+		}
+
+		if embedded, ok := inlinedType(field, format); ok {
+			for k, v := range fileKeys(embedded, format) {
+				keys[k] = v
+			}
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+			if format == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+
+		keys[name] = field.Type
+	}
+
+	return keys
+}
+
+/*
+inlinedType returns the struct type of the field if the decoder of the format reads its keys from the parent struct
+
+yaml.v3 inlines only fields with ",inline", while encoding/json and BurntSushi/toml flatten embedded structs without a name,
+yaml reads those from a key named after the type.
+*/
+func inlinedType(field reflect.StructField, format string) (reflect.Type, bool) {
+	name, opts, _ := strings.Cut(field.Tag.Get(format), ",")
+
+	switch format {
+	case "yaml":
+		if !slices.Contains(strings.Split(opts, ","), "inline") {
+			return nil, false
+		}
+	default:
+		if !field.Anonymous || name != "" {
+			return nil, false
+		}
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t, t.Kind() == reflect.Struct
+}
+
+// lookupKey matches key the same way the decoder of the format does:
+// yaml is case-sensitive, json and toml fall back to case-insensitive matching.
+func lookupKey(keys map[string]reflect.Type, key, format string) (reflect.Type, bool) {
+	if t, ok := keys[key]; ok {
+		return t, true
+	}
+
+	if format == "yaml" {
+		return nil, false
+	}
+
+	for name, t := range keys {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+func unknownError(sentinel error, name, suggestion string) error {
+	if suggestion == "" {
+		return fmt.Errorf("%w: %q", sentinel, name)
+	}
+
+	return fmt.Errorf("%w: %q, did you mean %q?", sentinel, name, suggestion)
+}
+
+func sortErrors(errs []error) {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+}
+
+// suggest returns the closest of candidates to name, or an empty string if none is close enough.
+func suggest(name string, candidates []string) string {
+	best, bestDist := "", -1
+
+	for _, candidate := range candidates {
+		dist := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDist == -1 || dist < bestDist || (dist == bestDist && candidate < best) {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	limit := len(name) / 3
+	if limit < 2 {
+		limit = 2
+	}
+
+	if bestDist == -1 || bestDist > limit {
+		return ""
+	}
+
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,101 @@
+package conf_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	conf "github.com/defany/platcom/v2/pkg/config"
+)
+
+type envConfig struct {
+	MaxConns int `env:"MAX_CONNS,required"`
+	DB       struct {
+		Host string `env:"HOST"`
+	} `envPrefix:"DB_"`
+}
+
+func TestReadStrictEnv(t *testing.T) {
+	t.Setenv("APP_MAX_CONNS", "5")
+	t.Setenv("APP_DB_HOST", "localhost")
+
+	cfg, err := readConfig(t, conf.NewReader[envConfig]().WithEnvPrefix("APP").WithStrict())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if cfg.MaxConns != 5 || cfg.DB.Host != "localhost" {
+		t.Errorf("Read() = %+v, want MaxConns 5 and DB.Host localhost", cfg)
+	}
+
+	t.Setenv("APP_DB_HSOT", "localhost")
+
+	_, err = readConfig(t, conf.NewReader[envConfig]().WithEnvPrefix("APP").WithStrict())
+	if !errors.Is(err, conf.ErrUnknownEnvVar) {
+		t.Fatalf("Read() error = %v, want ErrUnknownEnvVar", err)
+	}
+
+	if want := `"APP_DB_HSOT", did you mean "APP_DB_HOST"?`; !strings.Contains(err.Error(), want) {
+		t.Errorf("Read() error = %v, want it to mention %s", err, want)
+	}
+}
+
+type StrictBase struct {
+	Name string `json:"name" yaml:"name" toml:"name"`
+}
+
+type fileConfig struct {
+	StrictBase
+
+	Port int `json:"port" yaml:"port" toml:"port"`
+	DB   struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+	} `json:"db" yaml:"db" toml:"db"`
+}
+
+func TestReadStrictFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		unknown string
+	}{
+		{name: "json", file: "config.json", content: `{"name": "app", "port": 80, "db": {"host": "localhost"}}`},
+		{name: "json unknown", file: "config.json", content: `{"port": 80, "db": {"hots": "localhost"}}`, unknown: `"db.hots", did you mean "host"?`},
+		// yaml.v3 reads untagged embedded structs from a key named after the type
+		{name: "yaml", file: "config.yaml", content: "strictbase:\n  name: app\nport: 80\ndb:\n  host: localhost\n"},
+		{name: "yaml embedded key", file: "config.yaml", content: "name: app\nport: 80\n", unknown: `"name"`},
+		{name: "yaml unknown", file: "config.yaml", content: "prot: 80\n", unknown: `"prot", did you mean "port"?`},
+		{name: "toml", file: "config.toml", content: "name = \"app\"\nport = 80\n\n[db]\nhost = \"localhost\"\n"},
+		{name: "toml unknown", file: "config.toml", content: "port = 80\n\n[bd]\nhost = \"localhost\"\n", unknown: `"bd", did you mean "db"?`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := readConfig(t, conf.NewReader[fileConfig]().WithFilePath(path).WithStrict())
+
+			if tt.unknown != "" {
+				if !errors.Is(err, conf.ErrUnknownKey) || !strings.Contains(err.Error(), tt.unknown) {
+					t.Fatalf("Read() error = %v, want ErrUnknownKey for %s", err, tt.unknown)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			if cfg.Name != "app" || cfg.Port != 80 || cfg.DB.Host != "localhost" {
+				t.Errorf("Read() = %+v, want every key of the file", cfg)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package reflectx sets reflected values from text, it is shared by config readers and request decoders.
+package reflectx
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	DurationType        = reflect.TypeOf(time.Duration(0))
+	TextUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+/*
+SetValue parses raw into v according to its type
+
+Nil pointers are allocated, encoding.TextUnmarshaler and time.Duration are parsed with their own syntax,
+slices are parsed from comma separated values.
+*/
+func SetValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return SetValue(v.Elem(), raw)
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(TextUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	if v.Type() == DurationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+	case reflect.Slice:
+		if raw == "" {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+
+			return nil
+		}
+
+		parts := strings.Split(raw, ",")
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+
+		for i, part := range parts {
+			if err := SetValue(slice.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package budget
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	"github.com/defany/platcom/v2/pkg/perr/problem"
+)
+
+/*
+Handler records requests to the endpoint and errors returned by fn
+
+	mux.Handle("POST /users", rec.Handler("POST /users", h.CreateUser))
+
+The error is returned to the next layer as is, so it is rendered by the problem.HandlerFunc it becomes.
+*/
+func (r *Recorder) Handler(endpoint string, fn problem.HandlerFunc) problem.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		err := fn(w, req)
+		r.Record(endpoint, err)
+
+		return err
+	}
+}
+
+// UnaryServerInterceptor records calls by their full method and errors returned by handlers.
+// Chain it after grpcerr.UnaryServerInterceptor, so it is closer to handlers and sees errors before they become statuses.
+func (r *Recorder) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		r.Record(info.FullMethod, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streams by their full method and errors returned by handlers.
+// Chain it after grpcerr.StreamServerInterceptor, so it is closer to handlers and sees errors before they become statuses.
+func (r *Recorder) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		r.Record(info.FullMethod, err)
+
+		return err
+	}
+}
@@ -0,0 +1,65 @@
+package budget
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+/*
+WritePrometheus writes counts of the window in the Prometheus text format
+
+	# HELP perr_errors Errors in the sliding window by code, endpoint and error ID.
+	# TYPE perr_errors gauge
+	perr_errors{code="NOT_FOUND",endpoint="/users",id="user.not_found"} 3
+	# HELP perr_requests Requests in the sliding window by endpoint.
+	# TYPE perr_requests gauge
+	perr_requests{endpoint="/users"} 120
+
+Counts are gauges, since they go down as the window moves.
+*/
+func (r *Recorder) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# HELP perr_errors Errors in the sliding window by code, endpoint and error ID.")
+	fmt.Fprintln(bw, "# TYPE perr_errors gauge")
+
+	for _, sample := range r.Errors() {
+		fmt.Fprintf(bw, "perr_errors{code=\"%s\",endpoint=\"%s\",id=\"%s\"} %d\n",
+			sample.Code, labelEscaper.Replace(sample.Endpoint), labelEscaper.Replace(sample.ID), sample.Count)
+	}
+
+	requests := r.Requests()
+
+	endpoints := make([]string, 0, len(requests))
+	for endpoint := range requests {
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Strings(endpoints)
+
+	fmt.Fprintln(bw, "# HELP perr_requests Requests in the sliding window by endpoint.")
+	fmt.Fprintln(bw, "# TYPE perr_requests gauge")
+
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(bw, "perr_requests{endpoint=\"%s\"} %d\n", labelEscaper.Replace(endpoint), requests[endpoint])
+	}
+
+	return bw.Flush()
+}
+
+// MetricsHandler serves WritePrometheus, mount it on the metrics endpoint.
+func (r *Recorder) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		if err := r.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package budget
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+// Key identifies a series of errors.
+type Key struct {
+	Code     codes.Code
+	Endpoint string
+	// ID is the ID of the defined error, see perr.Define, empty for other errors.
+	ID string
+}
+
+// Sample is the number of errors of a series in the window.
+type Sample struct {
+	Key
+	Count uint64
+}
+
+/*
+Recorder counts requests and errors by code, endpoint and error ID over a sliding window. It is safe for concurrent use.
+
+The window is split into buckets, the oldest bucket is dropped as a whole once the window moves past it,
+so counts are accurate up to the duration of a bucket.
+*/
+type Recorder struct {
+	mu       sync.Mutex
+	window   time.Duration
+	buckets  int
+	now      func() time.Time
+	errors   map[Key]*counter
+	requests map[string]*counter
+}
+
+// NewRecorder creates a recorder with the window split into 60 buckets.
+func NewRecorder(window time.Duration) *Recorder {
+	return &Recorder{
+		window:   window,
+		buckets:  60,
+		now:      time.Now,
+		errors:   make(map[Key]*counter),
+		requests: make(map[string]*counter),
+	}
+}
+
+// WithBuckets sets the number of buckets the window is split into, more buckets make counts more accurate.
+func (r *Recorder) WithBuckets(buckets int) *Recorder {
+	r.buckets = max(buckets, 1)
+
+	return r
+}
+
+// WithClock sets the source of the current time, e.g. a fake one in tests.
+func (r *Recorder) WithClock(now func() time.Time) *Recorder {
+	r.now = now
+
+	return r
+}
+
+// Record counts a request to the endpoint, and the error if it isn't nil.
+func (r *Recorder) Record(endpoint string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.slot()
+
+	counterOf(r.requests, endpoint, r.buckets).add(slot)
+
+	if err != nil {
+		counterOf(r.errors, keyOf(endpoint, err), r.buckets).add(slot)
+	}
+}
+
+/*
+keyOf builds the key of the error
+
+Validation errors are counted as InvalidArgument, other errors by perr.CodeOf and the ID of the first *perr.Err in the chain.
+*/
+func keyOf(endpoint string, err error) Key {
+	key := Key{Code: perr.CodeOf(err), Endpoint: endpoint}
+
+	if validate.IsValidationError(err) {
+		key.Code = codes.InvalidArgument
+	}
+
+	if e := perr.ToCommonError(err); e != nil {
+		key.ID = e.ID()
+	}
+
+	return key
+}
+
+// Errors returns the number of errors of every series in the window, sorted by endpoint, code and ID.
+func (r *Recorder) Errors() []Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.slot()
+	samples := make([]Sample, 0, len(r.errors))
+
+	for key, c := range r.errors {
+		count := c.sum(slot)
+		if count == 0 {
+			delete(r.errors, key)
+
+			continue
+		}
+
+		samples = append(samples, Sample{Key: key, Count: count})
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i].Key, samples[j].Key
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+
+		return a.ID < b.ID
+	})
+
+	return samples
+}
+
+// Requests returns the number of requests of every endpoint in the window.
+func (r *Recorder) Requests() map[string]uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.slot()
+	requests := make(map[string]uint64, len(r.requests))
+
+	for endpoint, c := range r.requests {
+		count := c.sum(slot)
+		if count == 0 {
+			delete(r.requests, endpoint)
+
+			continue
+		}
+
+		requests[endpoint] = count
+	}
+
+	return requests
+}
+
+// slot returns the index of the current bucket since the epoch.
+func (r *Recorder) slot() int64 {
+	return r.now().UnixNano() / int64(r.bucketDuration())
+}
+
+func (r *Recorder) bucketDuration() time.Duration {
+	return max(r.window/time.Duration(r.buckets), 1)
+}
+
+func counterOf[K comparable](counters map[K]*counter, key K, buckets int) *counter {
+	c, ok := counters[key]
+	if !ok {
+		c = newCounter(buckets)
+		counters[key] = c
+	}
+
+	return c
+}
+
+// counter is a ring of buckets, each one remembers the slot it counts, so stale buckets are reset lazily.
+type counter struct {
+	counts []uint64
+	slots  []int64
+}
+
+func newCounter(buckets int) *counter {
+	return &counter{
+		counts: make([]uint64, buckets),
+		slots:  make([]int64, buckets),
+	}
+}
+
+func (c *counter) add(slot int64) {
+	i := int(slot % int64(len(c.counts)))
+
+	if c.slots[i] != slot {
+		c.slots[i] = slot
+		c.counts[i] = 0
+	}
+
+	c.counts[i]++
+}
+
+func (c *counter) sum(slot int64) uint64 {
+	var total uint64
+
+	for i, s := range c.slots {
+		if slot-s < int64(len(c.slots)) {
+			total += c.counts[i]
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,129 @@
+package budget_test
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/budget"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+var errUserNotFound = perr.Define("budget_test.user_not_found", codes.NotFound, "user %s not found")
+
+// clock is a fake source of time, moved forward by tests.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newRecorder() (*budget.Recorder, *clock) {
+	c := &clock{now: time.Unix(1700000000, 0)}
+
+	return budget.NewRecorder(time.Minute).WithBuckets(6).WithClock(c.Now), c
+}
+
+func TestRecorderWindow(t *testing.T) {
+	rec, c := newRecorder()
+
+	rec.Record("GET /users", perr.New("query failed", codes.Internal))
+
+	c.now = c.now.Add(30 * time.Second)
+	rec.Record("GET /users", nil)
+
+	if got := rec.Requests()["GET /users"]; got != 2 {
+		t.Errorf("Requests() = %d, want 2 in the window", got)
+	}
+
+	c.now = c.now.Add(40 * time.Second)
+
+	if got := rec.Requests()["GET /users"]; got != 1 {
+		t.Errorf("Requests() = %d, want 1 after the first bucket left the window", got)
+	}
+
+	if got := rec.Errors(); len(got) != 0 {
+		t.Errorf("Errors() = %+v, want none after the error left the window", got)
+	}
+
+	c.now = c.now.Add(time.Minute)
+
+	if got := rec.Requests(); len(got) != 0 {
+		t.Errorf("Requests() = %v, want none after the window moved past every request", got)
+	}
+}
+
+func TestRecorderEndpoints(t *testing.T) {
+	rec, _ := newRecorder()
+
+	rec.Record("GET /users", nil)
+	rec.Record("GET /users", errUserNotFound.New("bob"))
+	rec.Record("GET /users", errors.New("boom"))
+	rec.Record("POST /users", validate.NewError("name is required"))
+	rec.Record("POST /users", validate.NewError("name is required"))
+
+	wantRequests := map[string]uint64{"GET /users": 3, "POST /users": 2}
+	if got := rec.Requests(); !maps.Equal(got, wantRequests) {
+		t.Errorf("Requests() = %v, want %v", got, wantRequests)
+	}
+
+	wantErrors := []budget.Sample{
+		{Key: budget.Key{Code: codes.Unknown, Endpoint: "GET /users"}, Count: 1},
+		{Key: budget.Key{Code: codes.NotFound, Endpoint: "GET /users", ID: errUserNotFound.ID()}, Count: 1},
+		{Key: budget.Key{Code: codes.InvalidArgument, Endpoint: "POST /users"}, Count: 2},
+	}
+
+	if got := rec.Errors(); !slices.Equal(got, wantErrors) {
+		t.Errorf("Errors() = %+v, want %+v", got, wantErrors)
+	}
+}
+
+func TestBurnRate(t *testing.T) {
+	rec, _ := newRecorder()
+
+	for i := 0; i < 990; i++ {
+		rec.Record("GET /users", nil)
+	}
+
+	for i := 0; i < 2; i++ {
+		rec.Record("GET /users", perr.New("query failed", codes.Internal))
+	}
+
+	for i := 0; i < 8; i++ {
+		rec.Record("GET /users", perr.New("user not found", codes.NotFound))
+	}
+
+	rec.Record("GET /health", nil)
+
+	tests := []struct {
+		name string
+		slo  budget.SLO
+		want float64
+	}{
+		{name: "endpoint", slo: budget.SLO{Endpoint: "GET /users", Objective: 0.999}, want: 2},
+		{name: "all endpoints", slo: budget.SLO{Objective: 0.999}, want: 2.0 / 1001 / 0.001},
+		{name: "no errors", slo: budget.SLO{Endpoint: "GET /health", Objective: 0.999}, want: 0},
+		{name: "no requests", slo: budget.SLO{Endpoint: "GET /orders", Objective: 0.999}, want: 0},
+		{name: "no budget", slo: budget.SLO{Endpoint: "GET /users", Objective: 1}, want: math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rec.BurnRate(tt.slo)
+			if got != tt.want && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("BurnRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !rec.Burning(budget.SLO{Endpoint: "GET /users", Objective: 0.999}, 1) {
+		t.Error("Burning() = false, want true for a burn rate of 2 over the threshold of 1")
+	}
+}
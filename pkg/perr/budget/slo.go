@@ -0,0 +1,88 @@
+package budget
+
+import (
+	"math"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+/*
+SLO is an availability objective, e.g. 99.9% of requests to an endpoint succeed
+
+Errors with server-side codes, ones codes.HTTPStatus maps to 5xx, consume the error budget.
+Client-side errors like InvalidArgument or NotFound don't.
+*/
+type SLO struct {
+	// Endpoint is the endpoint the objective is for, empty for all endpoints.
+	Endpoint string
+	// Objective is the fraction of requests which must succeed, e.g. 0.999.
+	Objective float64
+}
+
+// IsBudgetCode reports whether errors with the code consume the error budget.
+func IsBudgetCode(c codes.Code) bool {
+	return codes.HTTPStatus(c) >= 500
+}
+
+/*
+BurnRate returns how fast the error budget of the SLO is consumed in the window
+
+1 means the budget is consumed exactly at the allowed pace, 10 means ten times faster. It is 0 if there were no errors.
+*/
+func (r *Recorder) BurnRate(slo SLO) float64 {
+	requests, bad := r.count(slo)
+
+	if requests == 0 {
+		return 0
+	}
+
+	if bad == 0 {
+		return 0
+	}
+
+	// an objective of 100% has no budget, so any error burns it infinitely fast
+	budget := 1 - slo.Objective
+	if budget <= 0 {
+		return math.Inf(1)
+	}
+
+	return float64(bad) / float64(requests) / budget
+}
+
+// count returns the number of requests and errors consuming the budget of the SLO in the window,
+// both taken under one lock, so a concurrent Record or a move of the window can't make them inconsistent.
+func (r *Recorder) count(slo SLO) (requests, bad uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.slot()
+
+	for endpoint, c := range r.requests {
+		if slo.Endpoint == "" || slo.Endpoint == endpoint {
+			requests += c.sum(slot)
+		}
+	}
+
+	for key, c := range r.errors {
+		if (slo.Endpoint == "" || slo.Endpoint == key.Endpoint) && IsBudgetCode(key.Code) {
+			bad += c.sum(slot)
+		}
+	}
+
+	return requests, bad
+}
+
+/*
+Burning reports whether the error budget of the SLO is consumed faster than the threshold allows
+
+Use it for alerts the way multiwindow burn rate alerts are built, e.g. 14.4 over an hour for a 30 days budget:
+
+	fast := budget.NewRecorder(time.Hour)
+
+	if fast.Burning(budget.SLO{Objective: 0.999}, 14.4) {
+		...
+	}
+*/
+func (r *Recorder) Burning(slo SLO, threshold float64) bool {
+	return r.BurnRate(slo) > threshold
+}
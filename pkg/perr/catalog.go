@@ -0,0 +1,245 @@
+package perr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+// Definition is a domain error defined once with a stable ID clients can switch on.
+type Definition struct {
+	id     string
+	code   codes.Code
+	format string
+}
+
+var (
+	definitionsMu sync.RWMutex
+	definitions   = make(map[string]*Definition)
+)
+
+/*
+Define defines a domain error with a stable ID, a code and a default message format in fmt syntax
+
+	var ErrUserNotFound = perr.Define("user.not_found", codes.NotFound, "user %s not found")
+
+	return ErrUserNotFound.New(id)
+
+It panics if the ID is already defined, so define errors in package-level variables.
+*/
+func Define(id string, code codes.Code, format string) *Definition {
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+
+	if _, ok := definitions[id]; ok {
+		panic(fmt.Sprintf("perr: error %q is already defined", id))
+	}
+
+	d := &Definition{id: id, code: code, format: format}
+	definitions[id] = d
+
+	return d
+}
+
+// Lookup returns the definition by ID.
+func Lookup(id string) (*Definition, bool) {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	d, ok := definitions[id]
+
+	return d, ok
+}
+
+func (d *Definition) ID() string {
+	return d.id
+}
+
+func (d *Definition) Code() codes.Code {
+	return d.code
+}
+
+// New creates an error of the definition with the message formatted with args.
+func (d *Definition) New(args ...any) *Err {
+	e := New(fmt.Sprintf(d.format, args...), d.code)
+	e.id = d.id
+	e.args = args
+
+	return e
+}
+
+// Wrap works like New, but keeps err as the cause.
+func (d *Definition) Wrap(err error, args ...any) *Err {
+	e := d.New(args...)
+	e.cause = err
+
+	return e
+}
+
+// Is reports whether any error in the chain of err is an instance of the definition.
+func (d *Definition) Is(err error) bool {
+	for err != nil {
+		var e *Err
+		if !errors.As(err, &e) {
+			return false
+		}
+
+		if e.id == d.id {
+			return true
+		}
+
+		err = e.cause
+	}
+
+	return false
+}
+
+/*
+NewWithID creates an error with the ID of a definition and the message as is, e.g. to restore a defined error
+received from another service, so Definition.Is matches it
+
+The definition doesn't have to be defined in this process.
+*/
+func NewWithID(id string, code codes.Code, msg string) *Err {
+	e := New(msg, code)
+	e.id = id
+
+	return e
+}
+
+// ID returns the ID of the definition the error was created from, if any.
+func (e *Err) ID() string {
+	return e.id
+}
+
+// Catalog keeps message formats of defined errors by locale and ID. It is safe for concurrent use.
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add adds message formats keyed by error ID for the locale, e.g. "ru" or "pt-BR".
+func (c *Catalog) Add(locale string, messages map[string]string) *Catalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+
+	for id, format := range messages {
+		c.messages[locale][id] = format
+	}
+
+	return c
+}
+
+/*
+Localize returns the message of the first defined error in the chain of err translated to the locale
+
+The exact locale is tried first, then its base language, e.g. "pt-BR" and then "pt".
+If there is no translation, the default message of the definition is returned, and PublicMessage of err if nothing was defined,
+so causes are never shown to clients
+*/
+func (c *Catalog) Localize(err error, locale string) string {
+	if err == nil {
+		return ""
+	}
+
+	e := definedError(err)
+	if e == nil {
+		return PublicMessage(err)
+	}
+
+	if format, ok := c.lookup(e.id, locale); ok {
+		return fmt.Sprintf(format, e.args...)
+	}
+
+	return e.msg
+}
+
+func (c *Catalog) lookup(id, locale string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locale = normalizeLocale(locale)
+
+	if format, ok := c.messages[locale][id]; ok {
+		return format, true
+	}
+
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		if format, ok := c.messages[base][id]; ok {
+			return format, true
+		}
+	}
+
+	return "", false
+}
+
+func definedError(err error) *Err {
+	for err != nil {
+		var e *Err
+		if !errors.As(err, &e) {
+			return nil
+		}
+
+		if e.id != "" {
+			return e
+		}
+
+		err = e.cause
+	}
+
+	return nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// DefaultCatalog is used by RegisterMessages and Localize.
+var DefaultCatalog = NewCatalog()
+
+// RegisterMessages adds message formats keyed by error ID for the locale to the default catalog.
+func RegisterMessages(locale string, messages map[string]string) {
+	DefaultCatalog.Add(locale, messages)
+}
+
+// Localize translates the message of err with the default catalog, see Catalog.Localize.
+func Localize(err error, locale string) string {
+	return DefaultCatalog.Localize(err, locale)
+}
+
+type localeKey struct{}
+
+// ContextWithLocale returns a copy of ctx carrying the locale of the request.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored by ContextWithLocale.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	locale, ok := ctx.Value(localeKey{}).(string)
+
+	return locale, ok && locale != ""
+}
+
+// LocalizeContext translates the message of err to the locale stored in ctx with the default catalog.
+func LocalizeContext(ctx context.Context, err error) string {
+	locale, _ := LocaleFromContext(ctx)
+
+	return Localize(err, locale)
+}
@@ -0,0 +1,41 @@
+package perr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+var errUserNotFound = perr.Define("perr_test.user_not_found", codes.NotFound, "user %s not found")
+
+func TestCatalogLocalize(t *testing.T) {
+	catalog := perr.NewCatalog().Add("ru", map[string]string{
+		errUserNotFound.ID(): "пользователь %s не найден",
+	})
+
+	dbErr := errors.New("pq: password authentication failed for user \"admin\"")
+
+	tests := []struct {
+		name   string
+		err    error
+		locale string
+		want   string
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "translated", err: errUserNotFound.New("bob"), locale: "ru-RU", want: "пользователь bob не найден"},
+		{name: "default message", err: errUserNotFound.Wrap(dbErr, "bob"), locale: "de", want: "user bob not found"},
+		{name: "not defined", err: perr.Wrap(dbErr, codes.NotFound, "user not found"), locale: "ru", want: "user not found"},
+		{name: "hidden code", err: perr.Wrap(dbErr, codes.Internal, "query failed"), locale: "ru", want: perr.GenericMessage},
+		{name: "plain error", err: dbErr, locale: "ru", want: perr.GenericMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalog.Localize(tt.err, tt.locale); got != tt.want {
+				t.Errorf("Localize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
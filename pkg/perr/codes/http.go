@@ -0,0 +1,71 @@
+package codes
+
+import "net/http"
+
+// StatusClientClosedRequest is a non-standard status used by nginx and grpc-gateway for canceled requests.
+const StatusClientClosedRequest = 499
+
+// HTTPStatus maps the code to an HTTP status the same way grpc-gateway does.
+// Unknown codes are mapped to 500 Internal Server Error.
+func HTTPStatus(c Code) int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case Canceled:
+		return StatusClientClosedRequest
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return http.StatusBadRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Unimplemented:
+		return http.StatusNotImplemented
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FromHTTPStatus maps an HTTP status to the closest code, e.g. for responses of other services.
+func FromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return OK
+	case StatusClientClosedRequest:
+		return Canceled
+	case http.StatusBadRequest:
+		return InvalidArgument
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return AlreadyExists
+	case http.StatusTooManyRequests:
+		return ResourceExhausted
+	case http.StatusNotImplemented:
+		return Unimplemented
+	case http.StatusPreconditionFailed:
+		return FailedPrecondition
+	case http.StatusGatewayTimeout:
+		return DeadlineExceeded
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return Unavailable
+	case http.StatusInternalServerError:
+		return Internal
+	default:
+		return Unknown
+	}
+}
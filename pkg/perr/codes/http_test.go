@@ -0,0 +1,74 @@
+package codes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code   codes.Code
+		status int
+	}{
+		{code: codes.OK, status: http.StatusOK},
+		{code: codes.Canceled, status: codes.StatusClientClosedRequest},
+		{code: codes.Unknown, status: http.StatusInternalServerError},
+		{code: codes.InvalidArgument, status: http.StatusBadRequest},
+		{code: codes.DeadlineExceeded, status: http.StatusGatewayTimeout},
+		{code: codes.NotFound, status: http.StatusNotFound},
+		{code: codes.AlreadyExists, status: http.StatusConflict},
+		{code: codes.PermissionDenied, status: http.StatusForbidden},
+		{code: codes.ResourceExhausted, status: http.StatusTooManyRequests},
+		{code: codes.FailedPrecondition, status: http.StatusBadRequest},
+		{code: codes.Aborted, status: http.StatusConflict},
+		{code: codes.OutOfRange, status: http.StatusBadRequest},
+		{code: codes.Unimplemented, status: http.StatusNotImplemented},
+		{code: codes.Internal, status: http.StatusInternalServerError},
+		{code: codes.Unavailable, status: http.StatusServiceUnavailable},
+		{code: codes.DataLoss, status: http.StatusInternalServerError},
+		{code: codes.Unauthenticated, status: http.StatusUnauthorized},
+		{code: codes.Code(42), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := codes.HTTPStatus(tt.code); got != tt.status {
+				t.Errorf("HTTPStatus(%v) = %d, want %d", tt.code, got, tt.status)
+			}
+		})
+	}
+}
+
+func TestFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		code   codes.Code
+	}{
+		{status: http.StatusOK, code: codes.OK},
+		{status: http.StatusNoContent, code: codes.OK},
+		{status: codes.StatusClientClosedRequest, code: codes.Canceled},
+		{status: http.StatusBadRequest, code: codes.InvalidArgument},
+		{status: http.StatusUnauthorized, code: codes.Unauthenticated},
+		{status: http.StatusForbidden, code: codes.PermissionDenied},
+		{status: http.StatusNotFound, code: codes.NotFound},
+		{status: http.StatusConflict, code: codes.AlreadyExists},
+		{status: http.StatusPreconditionFailed, code: codes.FailedPrecondition},
+		{status: http.StatusTooManyRequests, code: codes.ResourceExhausted},
+		{status: http.StatusInternalServerError, code: codes.Internal},
+		{status: http.StatusNotImplemented, code: codes.Unimplemented},
+		{status: http.StatusBadGateway, code: codes.Unavailable},
+		{status: http.StatusServiceUnavailable, code: codes.Unavailable},
+		{status: http.StatusGatewayTimeout, code: codes.DeadlineExceeded},
+		{status: http.StatusTeapot, code: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			if got := codes.FromHTTPStatus(tt.status); got != tt.code {
+				t.Errorf("FromHTTPStatus(%d) = %v, want %v", tt.status, got, tt.code)
+			}
+		})
+	}
+}
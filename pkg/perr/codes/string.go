@@ -0,0 +1,127 @@
+package codes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// names are canonical names of codes as in google.rpc.Code.
+var names = map[Code]string{
+	OK:                 "OK",
+	Canceled:           "CANCELLED",
+	Unknown:            "UNKNOWN",
+	InvalidArgument:    "INVALID_ARGUMENT",
+	DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	NotFound:           "NOT_FOUND",
+	AlreadyExists:      "ALREADY_EXISTS",
+	PermissionDenied:   "PERMISSION_DENIED",
+	ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	FailedPrecondition: "FAILED_PRECONDITION",
+	Aborted:            "ABORTED",
+	OutOfRange:         "OUT_OF_RANGE",
+	Unimplemented:      "UNIMPLEMENTED",
+	Internal:           "INTERNAL",
+	Unavailable:        "UNAVAILABLE",
+	DataLoss:           "DATA_LOSS",
+	Unauthenticated:    "UNAUTHENTICATED",
+}
+
+var byName = func() map[string]Code {
+	m := make(map[string]Code, len(names)+1)
+	for c, name := range names {
+		m[name] = c
+	}
+
+	// American spelling used by grpc-go
+	m["CANCELED"] = Canceled
+
+	return m
+}()
+
+// String returns the canonical name of the code, e.g. "INVALID_ARGUMENT".
+func (c Code) String() string {
+	if name, ok := names[c]; ok {
+		return name
+	}
+
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+/*
+ParseCode parses a canonical name of the code, e.g. "INVALID_ARGUMENT"
+
+Names are case-insensitive, numeric values like "3" and the "Code(42)" form String returns for unknown codes
+are accepted as well
+*/
+func ParseCode(s string) (Code, error) {
+	s = strings.TrimSpace(s)
+
+	if c, ok := byName[strings.ToUpper(s)]; ok {
+		return c, nil
+	}
+
+	num := s
+	if inner, ok := strings.CutPrefix(s, "Code("); ok {
+		num = strings.TrimSuffix(inner, ")")
+	}
+
+	if n, err := strconv.ParseUint(num, 10, 32); err == nil {
+		return Code(n), nil
+	}
+
+	return Unknown, fmt.Errorf("unknown code %q", s)
+}
+
+// MarshalText encodes the code by its canonical name, codes without one are encoded as numbers, e.g. "42".
+func (c Code) MarshalText() ([]byte, error) {
+	if name, ok := names[c]; ok {
+		return []byte(name), nil
+	}
+
+	return []byte(strconv.FormatUint(uint64(c), 10)), nil
+}
+
+func (c *Code) UnmarshalText(text []byte) error {
+	parsed, err := ParseCode(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+
+	return nil
+}
+
+// UnmarshalJSON accepts both names and numbers, so payloads with numeric codes are still readable.
+func (c *Code) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		return c.UnmarshalText([]byte(s))
+	}
+
+	var n uint32
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*c = Code(n)
+
+	return nil
+}
+
+// LogValue implements slog.LogValuer, so the code is logged by its name.
+func (c Code) LogValue() slog.Value {
+	return slog.StringValue(c.String())
+}
@@ -0,0 +1,65 @@
+package codes_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+func TestCodeText(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		text string
+	}{
+		{code: codes.OK, text: "OK"},
+		{code: codes.InvalidArgument, text: "INVALID_ARGUMENT"},
+		{code: codes.Code(42), text: "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			text, err := tt.code.MarshalText()
+			if err != nil || string(text) != tt.text {
+				t.Fatalf("MarshalText() = %q, %v, want %q", text, err, tt.text)
+			}
+
+			var got codes.Code
+			if err := got.UnmarshalText(text); err != nil || got != tt.code {
+				t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, tt.code)
+			}
+
+			data, err := json.Marshal(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal(data, &got); err != nil || got != tt.code {
+				t.Errorf("json round trip of %s = %v, %v, want %v", data, got, err, tt.code)
+			}
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    codes.Code
+		wantErr bool
+	}{
+		{in: "not_found", want: codes.NotFound},
+		{in: "CANCELED", want: codes.Canceled},
+		{in: "5", want: codes.NotFound},
+		{in: "Code(42)", want: codes.Code(42)},
+		{in: codes.Code(42).String(), want: codes.Code(42)},
+		{in: "Code(x)", wantErr: true},
+		{in: "nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := codes.ParseCode(tt.in)
+		if (err != nil) != tt.wantErr || !tt.wantErr && got != tt.want {
+			t.Errorf("ParseCode(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package perr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+/*
+As finds the first error in the chain of err of type T, like errors.As without a target variable
+
+	if e, ok := perr.As[*perr.Err](err); ok {
+		...
+	}
+*/
+func As[T error](err error) (T, bool) {
+	var target T
+	ok := errors.As(err, &target)
+
+	return target, ok
+}
+
+type stdCode struct {
+	target error
+	code   codes.Code
+	msg    string
+}
+
+var (
+	stdCodesMu sync.RWMutex
+	// stdCodes are checked in order, so more specific errors go first, e.g. os.ErrDeadlineExceeded wraps nothing,
+	// but is reported by deadlines of files and connections, not contexts
+	stdCodes = []stdCode{
+		{target: context.Canceled, code: codes.Canceled, msg: "canceled"},
+		{target: context.DeadlineExceeded, code: codes.DeadlineExceeded, msg: "deadline exceeded"},
+		{target: os.ErrDeadlineExceeded, code: codes.DeadlineExceeded, msg: "deadline exceeded"},
+		{target: sql.ErrNoRows, code: codes.NotFound, msg: "not found"},
+		{target: fs.ErrNotExist, code: codes.NotFound, msg: "not found"},
+		{target: fs.ErrExist, code: codes.AlreadyExists, msg: "already exists"},
+		{target: fs.ErrPermission, code: codes.PermissionDenied, msg: "permission denied"},
+		{target: sql.ErrTxDone, code: codes.FailedPrecondition, msg: "transaction is already done"},
+		{target: sql.ErrConnDone, code: codes.Unavailable, msg: "connection is closed"},
+		{target: net.ErrClosed, code: codes.Unavailable, msg: "connection is closed"},
+		{target: errors.ErrUnsupported, code: codes.Unimplemented, msg: "unsupported"},
+	}
+)
+
+/*
+RegisterCode maps errors matching target with errors.Is to the code in CodeOf and Convert, e.g. sentinels of drivers
+
+	perr.RegisterCode(pgx.ErrNoRows, codes.NotFound, "not found")
+
+The message is used by Convert. Registered errors are checked before the standard ones.
+*/
+func RegisterCode(target error, code codes.Code, msg string) {
+	stdCodesMu.Lock()
+	defer stdCodesMu.Unlock()
+
+	stdCodes = append([]stdCode{{target: target, code: code, msg: msg}}, stdCodes...)
+}
+
+func lookupStdCode(err error) (stdCode, bool) {
+	stdCodesMu.RLock()
+	defer stdCodesMu.RUnlock()
+
+	for _, c := range stdCodes {
+		if errors.Is(err, c.target) {
+			return c, true
+		}
+	}
+
+	return stdCode{}, false
+}
+
+/*
+CodeOf returns the code of err
+
+It is OK for nil, the code of the first *Err in the chain, the code of a known standard error like context.Canceled,
+sql.ErrNoRows or os.ErrNotExist, see RegisterCode, and Unknown otherwise.
+*/
+func CodeOf(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	if e, ok := As[*Err](err); ok {
+		return e.code
+	}
+
+	if c, ok := lookupStdCode(err); ok {
+		return c.code
+	}
+
+	return codes.Unknown
+}
+
+/*
+Convert returns the first *Err in the chain of err, or wraps err into *Err with the code of CodeOf
+
+Known standard errors get a short message of their own, anything else gets Unknown and GenericMessage. It returns nil for nil.
+*/
+func Convert(err error) *Err {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := As[*Err](err); ok {
+		return e
+	}
+
+	if c, ok := lookupStdCode(err); ok {
+		return Wrap(err, c.code, c.msg)
+	}
+
+	return Wrap(err, codes.Unknown, GenericMessage)
+}
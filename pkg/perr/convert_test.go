@@ -0,0 +1,177 @@
+package perr_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+var errNotFound = perr.New("user not found", codes.NotFound)
+
+func TestToCommonError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *perr.Err
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "plain error", err: errors.New("boom"), want: nil},
+		{name: "perr error", err: errNotFound, want: errNotFound},
+		{name: "wrapped with fmt", err: fmt.Errorf("get user: %w", errNotFound), want: errNotFound},
+		{name: "joined", err: errors.Join(errors.New("boom"), errNotFound), want: errNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := perr.ToCommonError(tt.err); got != tt.want {
+				t.Errorf("ToCommonError() = %v, want %v", got, tt.want)
+			}
+
+			if got := perr.IsCommonError(tt.err); got != (tt.want != nil) {
+				t.Errorf("IsCommonError() = %v, want %v", got, tt.want != nil)
+			}
+		})
+	}
+}
+
+func TestAs(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/nope", Err: fs.ErrNotExist}
+
+	tests := []struct {
+		name   string
+		err    error
+		want   *fs.PathError
+		wantOk bool
+	}{
+		{name: "nil", err: nil, want: nil, wantOk: false},
+		{name: "other type", err: errNotFound, want: nil, wantOk: false},
+		{name: "same error", err: pathErr, want: pathErr, wantOk: true},
+		{name: "wrapped", err: perr.Wrap(pathErr, codes.Internal, "read config"), want: pathErr, wantOk: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := perr.As[*fs.PathError](tt.err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("As() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "nil", err: nil, want: codes.OK},
+		{name: "plain error", err: errors.New("boom"), want: codes.Unknown},
+		{name: "perr error", err: errNotFound, want: codes.NotFound},
+		{name: "perr wins over cause", err: perr.Wrap(context.Canceled, codes.Internal, "query"), want: codes.Internal},
+		{name: "context canceled", err: context.Canceled, want: codes.Canceled},
+		{name: "context deadline", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: codes.DeadlineExceeded},
+		{name: "os deadline", err: os.ErrDeadlineExceeded, want: codes.DeadlineExceeded},
+		{name: "no rows", err: fmt.Errorf("get user: %w", sql.ErrNoRows), want: codes.NotFound},
+		{name: "not exist", err: &fs.PathError{Op: "open", Path: "/nope", Err: fs.ErrNotExist}, want: codes.NotFound},
+		{name: "exist", err: os.ErrExist, want: codes.AlreadyExists},
+		{name: "permission", err: os.ErrPermission, want: codes.PermissionDenied},
+		{name: "tx done", err: sql.ErrTxDone, want: codes.FailedPrecondition},
+		{name: "conn done", err: sql.ErrConnDone, want: codes.Unavailable},
+		{name: "unsupported", err: errors.ErrUnsupported, want: codes.Unimplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := perr.CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	errLocked := errors.New("row is locked")
+	perr.RegisterCode(errLocked, codes.Aborted, "locked")
+
+	err := fmt.Errorf("update: %w", errLocked)
+
+	if got := perr.CodeOf(err); got != codes.Aborted {
+		t.Errorf("CodeOf() = %v, want %v", got, codes.Aborted)
+	}
+
+	if got := perr.Convert(err).Message(); got != "locked" {
+		t.Errorf("Convert().Message() = %q, want %q", got, "locked")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	plain := errors.New("boom")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantNil  bool
+		wantCode codes.Code
+		wantMsg  string
+	}{
+		{name: "nil", err: nil, wantNil: true},
+		{name: "perr error is returned as is", err: fmt.Errorf("wrap: %w", errNotFound), wantCode: codes.NotFound, wantMsg: "user not found"},
+		{name: "canceled", err: context.Canceled, wantCode: codes.Canceled, wantMsg: "canceled"},
+		{name: "no rows", err: sql.ErrNoRows, wantCode: codes.NotFound, wantMsg: "not found"},
+		{name: "unknown", err: plain, wantCode: codes.Unknown, wantMsg: perr.GenericMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := perr.Convert(tt.err)
+
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("Convert() = %v, want nil", got)
+				}
+
+				return
+			}
+
+			if got.Code() != tt.wantCode || got.Message() != tt.wantMsg {
+				t.Errorf("Convert() = %v %q, want %v %q", got.Code(), got.Message(), tt.wantCode, tt.wantMsg)
+			}
+
+			// either err is wrapped by the result, or the result is found in the chain of err
+			if !errors.Is(got, tt.err) && !errors.Is(tt.err, got) {
+				t.Errorf("Convert() = %v is not related to %v", got, tt.err)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: perr.ExitOK},
+		{name: "plain error", err: errors.New("boom"), want: perr.ExitSoftware},
+		{name: "canceled", err: context.Canceled, want: perr.ExitInterrupted},
+		{name: "deadline", err: context.DeadlineExceeded, want: perr.ExitTempFail},
+		{name: "not found", err: errNotFound, want: perr.ExitNoInput},
+		{name: "not exist", err: os.ErrNotExist, want: perr.ExitNoInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := perr.ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package perr
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Detail is a typed detail attached to Err, modelled after google.rpc error details.
+type Detail interface {
+	isDetail()
+}
+
+// FieldViolation describes a single bad field of a request.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// BadRequest describes violations in a client request.
+type BadRequest struct {
+	FieldViolations []FieldViolation `json:"fieldViolations"`
+}
+
+// RetryInfo tells the client when it can retry the request.
+type RetryInfo struct {
+	RetryDelay time.Duration `json:"retryDelay"`
+}
+
+// ResourceInfo describes the resource being accessed.
+type ResourceInfo struct {
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	Owner        string `json:"owner,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// QuotaViolation describes a single quota violation, e.g. a daily limit that was exceeded.
+type QuotaViolation struct {
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// QuotaFailure describes how a quota check failed.
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations"`
+}
+
+// ErrorInfo describes the cause of the error: a machine-readable reason within a domain and arbitrary metadata.
+type ErrorInfo struct {
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+func (*BadRequest) isDetail()   {}
+func (*RetryInfo) isDetail()    {}
+func (*ResourceInfo) isDetail() {}
+func (*QuotaFailure) isDetail() {}
+func (*ErrorInfo) isDetail()    {}
+
+// typeURLPrefix prefixes names of google.rpc details in type URLs of google.protobuf.Any.
+const typeURLPrefix = "type.googleapis.com/google.rpc."
+
+/*
+MarshalJSON encodes the detail like google.rpc JSON does, with the type URL of its counterpart in "@type"
+
+	{"@type": "type.googleapis.com/google.rpc.BadRequest", "fieldViolations": [...]}
+*/
+func (b BadRequest) MarshalJSON() ([]byte, error) {
+	type plain BadRequest
+
+	return withType("BadRequest", plain(b))
+}
+
+// MarshalJSON encodes the delay as seconds like google.protobuf.Duration does, e.g. "1.5s", see BadRequest.MarshalJSON.
+func (r RetryInfo) MarshalJSON() ([]byte, error) {
+	return withType("RetryInfo", map[string]string{
+		"retryDelay": strconv.FormatFloat(r.RetryDelay.Seconds(), 'f', -1, 64) + "s",
+	})
+}
+
+// MarshalJSON encodes the detail with its type URL, see BadRequest.MarshalJSON.
+func (r ResourceInfo) MarshalJSON() ([]byte, error) {
+	type plain ResourceInfo
+
+	return withType("ResourceInfo", plain(r))
+}
+
+// MarshalJSON encodes the detail with its type URL, see BadRequest.MarshalJSON.
+func (q QuotaFailure) MarshalJSON() ([]byte, error) {
+	type plain QuotaFailure
+
+	return withType("QuotaFailure", plain(q))
+}
+
+// MarshalJSON encodes the detail with its type URL, see BadRequest.MarshalJSON.
+func (i ErrorInfo) MarshalJSON() ([]byte, error) {
+	type plain ErrorInfo
+
+	return withType("ErrorInfo", plain(i))
+}
+
+// withType encodes the JSON object v with "@type" of the google.rpc detail named name first.
+func withType(name string, v any) ([]byte, error) {
+	obj, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	head := `{"@type":"` + typeURLPrefix + name + `"`
+	if len(obj) <= len("{}") {
+		return []byte(head + "}"), nil
+	}
+
+	return append([]byte(head+","), obj[1:]...), nil
+}
+
+/*
+WithDetail returns a copy of the error with the detail attached
+
+Like every With* method it never modifies the receiver, so sentinels and errors shared between requests can be decorated
+concurrently without piling up details:
+
+	var ErrOutOfStock = perr.New("out of stock", codes.FailedPrecondition)
+
+	return ErrOutOfStock.WithResource("sku", sku)
+*/
+func (e *Err) WithDetail(detail Detail) *Err {
+	c := e.clone()
+	c.details = append(c.details, detail)
+
+	return c
+}
+
+// WithFieldViolation returns a copy of the error with a field violation added to its BadRequest detail.
+func (e *Err) WithFieldViolation(field, description string) *Err {
+	c := e.clone()
+
+	br := ownDetail[BadRequest](c)
+	br.FieldViolations = append(slices.Clip(br.FieldViolations), FieldViolation{Field: field, Description: description})
+
+	return c
+}
+
+// WithRetryAfter returns a copy of the error telling the client to retry the request after d.
+func (e *Err) WithRetryAfter(d time.Duration) *Err {
+	c := e.clone()
+
+	ownDetail[RetryInfo](c).RetryDelay = d
+
+	return c
+}
+
+// WithResource returns a copy of the error describing the resource the error relates to.
+func (e *Err) WithResource(resourceType, resourceName string) *Err {
+	return e.WithDetail(&ResourceInfo{ResourceType: resourceType, ResourceName: resourceName})
+}
+
+// WithQuotaViolation returns a copy of the error with a quota violation added to its QuotaFailure detail.
+func (e *Err) WithQuotaViolation(subject, description string) *Err {
+	c := e.clone()
+
+	qf := ownDetail[QuotaFailure](c)
+	qf.Violations = append(slices.Clip(qf.Violations), QuotaViolation{Subject: subject, Description: description})
+
+	return c
+}
+
+// WithReason returns a copy of the error with a machine-readable reason within the domain,
+// e.g. "STOCK_DEPLETED" in "orders.example.com".
+func (e *Err) WithReason(reason, domain string) *Err {
+	c := e.clone()
+
+	info := ownDetail[ErrorInfo](c)
+	info.Reason = reason
+	info.Domain = domain
+
+	return c
+}
+
+// WithMetadata returns a copy of the error with a key/value pair added to its ErrorInfo detail.
+func (e *Err) WithMetadata(key, value string) *Err {
+	c := e.clone()
+
+	info := ownDetail[ErrorInfo](c)
+	info.Metadata = maps.Clone(info.Metadata)
+	if info.Metadata == nil {
+		info.Metadata = make(map[string]string)
+	}
+
+	info.Metadata[key] = value
+
+	return c
+}
+
+// Details returns the details attached to the error.
+func (e *Err) Details() []Detail {
+	return e.details
+}
+
+// DetailOf returns the first detail of type D attached to any *Err in the chain of err.
+func DetailOf[D Detail](err error) (D, bool) {
+	var zero D
+
+	for err != nil {
+		var e *Err
+		if !errors.As(err, &e) {
+			return zero, false
+		}
+
+		if d, ok := detailOf[D](e); ok {
+			return d, true
+		}
+
+		err = e.cause
+	}
+
+	return zero, false
+}
+
+// clone returns a shallow copy of the error with its own slice of details, With* methods decorate copies.
+func (e *Err) clone() *Err {
+	c := *e
+	c.details = slices.Clone(e.details)
+
+	return &c
+}
+
+// ownDetail returns the detail of type *T of the copy e to modify, it replaces the shared detail with a shallow copy,
+// or adds a new detail if there is none.
+func ownDetail[T any, D interface {
+	*T
+	Detail
+}](e *Err) D {
+	for i, detail := range e.details {
+		if d, ok := detail.(D); ok {
+			c := D(new(T))
+			*c = *d
+			e.details[i] = c
+
+			return c
+		}
+	}
+
+	d := D(new(T))
+	e.details = append(e.details, d)
+
+	return d
+}
+
+func detailOf[D Detail](e *Err) (D, bool) {
+	for _, detail := range e.details {
+		if d, ok := detail.(D); ok {
+			return d, true
+		}
+	}
+
+	var zero D
+
+	return zero, false
+}
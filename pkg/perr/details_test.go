@@ -0,0 +1,84 @@
+package perr_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+func TestWithDetailsCopy(t *testing.T) {
+	base := perr.New("bad request", codes.InvalidArgument).
+		WithFieldViolation("name", "is required").
+		WithMetadata("service", "users")
+
+	first := base.WithFieldViolation("email", "is invalid").WithMetadata("request", "1")
+	second := base.WithFieldViolation("age", "is negative").WithStack().WithInternal("debug")
+
+	fields := func(e *perr.Err) []string {
+		br, _ := perr.DetailOf[*perr.BadRequest](e)
+
+		var names []string
+		for _, v := range br.FieldViolations {
+			names = append(names, v.Field)
+		}
+
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		err      *perr.Err
+		fields   []string
+		metadata int
+	}{
+		{name: "base", err: base, fields: []string{"name"}, metadata: 1},
+		{name: "first", err: first, fields: []string{"name", "email"}, metadata: 2},
+		{name: "second", err: second, fields: []string{"name", "age"}, metadata: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(tt.err); len(got) != len(tt.fields) || got[0] != tt.fields[0] || got[len(got)-1] != tt.fields[len(tt.fields)-1] {
+				t.Errorf("field violations = %v, want %v", got, tt.fields)
+			}
+
+			info, _ := perr.DetailOf[*perr.ErrorInfo](tt.err)
+			if len(info.Metadata) != tt.metadata {
+				t.Errorf("metadata = %v, want %d keys", info.Metadata, tt.metadata)
+			}
+		})
+	}
+
+	if base.Stack() != nil || base.Internal() != "" {
+		t.Errorf("base got the stack or internal message of a copy")
+	}
+}
+
+func TestDetailsJSON(t *testing.T) {
+	err := perr.New("out of stock", codes.FailedPrecondition).
+		WithFieldViolation("count", "is too big").
+		WithRetryAfter(1500*time.Millisecond).
+		WithResource("sku", "42").
+		WithQuotaViolation("user:1", "daily limit").
+		WithReason("STOCK_DEPLETED", "orders.example.com")
+
+	got, merr := json.Marshal(err.Details())
+	if merr != nil {
+		t.Fatal(merr)
+	}
+
+	want := `[` +
+		`{"@type":"type.googleapis.com/google.rpc.BadRequest","fieldViolations":[{"field":"count","description":"is too big"}]},` +
+		`{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"1.5s"},` +
+		`{"@type":"type.googleapis.com/google.rpc.ResourceInfo","resourceType":"sku","resourceName":"42"},` +
+		`{"@type":"type.googleapis.com/google.rpc.QuotaFailure","violations":[{"subject":"user:1","description":"daily limit"}]},` +
+		`{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"STOCK_DEPLETED","domain":"orders.example.com"}` +
+		`]`
+
+	if string(got) != want {
+		t.Errorf("json.Marshal(details) =\n%s\nwant\n%s", got, want)
+	}
+}
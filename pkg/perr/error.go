@@ -1,38 +1,105 @@
 package perr
 
 import (
-	"errors"
+	"fmt"
+	"io"
 
 	"github.com/defany/platcom/v2/pkg/perr/codes"
 )
 
 type Err struct {
-	msg  string
-	code codes.Code
+	msg      string
+	code     codes.Code
+	internal string
+	cause    error
+	stack    Stack
+
+	details []Detail
+
+	id   string
+	args []any
 }
 
 func New(msg string, code codes.Code) *Err {
 	return &Err{msg: msg, code: code}
 }
 
+// Wrap creates an error with the code that keeps err as its cause, so errors.Is and errors.As see through it.
+// If err is nil, Wrap works like New.
+func Wrap(err error, code codes.Code, msg string) *Err {
+	return &Err{msg: msg, code: code, cause: err}
+}
+
+// WithStack returns a copy of the error with the stack trace of the caller.
+func (e *Err) WithStack() *Err {
+	c := e.clone()
+	c.stack = callers(3)
+
+	return c
+}
+
 func (e *Err) Error() string {
-	return e.msg
+	if e.cause == nil {
+		return e.msg
+	}
+
+	return e.msg + ": " + e.cause.Error()
 }
 
 func (e *Err) Code() codes.Code {
 	return e.code
 }
 
+// Message returns the message of the error without its cause.
+func (e *Err) Message() string {
+	return e.msg
+}
+
+func (e *Err) Unwrap() error {
+	return e.cause
+}
+
+// Stack returns the stack trace captured by WithStack, if any.
+func (e *Err) Stack() Stack {
+	return e.stack
+}
+
+/*
+Format implements fmt.Formatter
+
+%s and %v print the message with the cause chain, %+v adds the internal message, causes formatted with %+v and the stack trace
+*/
+func (e *Err) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		_, _ = io.WriteString(s, e.msg)
+
+		if e.internal != "" {
+			_, _ = fmt.Fprintf(s, "\ninternal: %s", e.internal)
+		}
+
+		if e.cause != nil {
+			_, _ = fmt.Fprintf(s, "\ncaused by: %+v", e.cause)
+		}
+
+		if len(e.stack) > 0 {
+			_, _ = fmt.Fprintf(s, "\n%v", e.stack)
+		}
+	case verb == 'v' || verb == 's':
+		_, _ = io.WriteString(s, e.Error())
+	case verb == 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
 func IsCommonError(err error) bool {
-	var ce *Err
-	return errors.As(err, &ce)
+	_, ok := As[*Err](err)
+	return ok
 }
 
+// ToCommonError returns the first *Err in the chain of err, or nil if there is none.
 func ToCommonError(err error) *Err {
-	var ce *Err
-	if !IsCommonError(err) {
-		return nil
-	}
+	ce, _ := As[*Err](err)
 
 	return ce
 }
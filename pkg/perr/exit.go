@@ -0,0 +1,73 @@
+package perr
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+// Exit codes in the spirit of sysexits.h.
+const (
+	ExitOK          = 0
+	ExitUsage       = 64  // EX_USAGE: command line usage error
+	ExitDataErr     = 65  // EX_DATAERR: data format error
+	ExitNoInput     = 66  // EX_NOINPUT: cannot open input
+	ExitUnavailable = 69  // EX_UNAVAILABLE: service unavailable
+	ExitSoftware    = 70  // EX_SOFTWARE: internal software error
+	ExitCantCreate  = 73  // EX_CANTCREAT: can't create output
+	ExitIOErr       = 74  // EX_IOERR: input/output error
+	ExitTempFail    = 75  // EX_TEMPFAIL: temporary failure, the user is invited to retry
+	ExitNoPerm      = 77  // EX_NOPERM: permission denied
+	ExitConfig      = 78  // EX_CONFIG: configuration error
+	ExitInterrupted = 130 // terminated by SIGINT, as shells report it
+)
+
+/*
+ExitCode maps err to a process exit code by its code, see CodeOf
+
+Errors without a known code are mapped to ExitSoftware
+*/
+func ExitCode(err error) int {
+	return ExitCodeOf(CodeOf(err))
+}
+
+// ExitCodeOf maps the code to a process exit code.
+func ExitCodeOf(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return ExitOK
+	case codes.Canceled:
+		return ExitInterrupted
+	case codes.InvalidArgument:
+		return ExitUsage
+	case codes.OutOfRange:
+		return ExitDataErr
+	case codes.NotFound:
+		return ExitNoInput
+	case codes.AlreadyExists:
+		return ExitCantCreate
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ExitNoPerm
+	case codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return ExitTempFail
+	case codes.FailedPrecondition:
+		return ExitConfig
+	case codes.Unavailable:
+		return ExitUnavailable
+	case codes.DataLoss:
+		return ExitIOErr
+	default:
+		return ExitSoftware
+	}
+}
+
+// ExitSignal maps the signal a process was terminated by to an exit code the way shells report it, 128 plus the signal number,
+// e.g. 130 for SIGINT and 143 for SIGTERM. Signals without a number are mapped to ExitInterrupted.
+func ExitSignal(sig os.Signal) int {
+	if n, ok := sig.(syscall.Signal); ok {
+		return 128 + int(n)
+	}
+
+	return ExitInterrupted
+}
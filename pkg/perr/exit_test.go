@@ -0,0 +1,60 @@
+package perr_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:                 perr.ExitOK,
+		codes.Canceled:           perr.ExitInterrupted,
+		codes.Unknown:            perr.ExitSoftware,
+		codes.InvalidArgument:    perr.ExitUsage,
+		codes.DeadlineExceeded:   perr.ExitTempFail,
+		codes.NotFound:           perr.ExitNoInput,
+		codes.AlreadyExists:      perr.ExitCantCreate,
+		codes.PermissionDenied:   perr.ExitNoPerm,
+		codes.ResourceExhausted:  perr.ExitTempFail,
+		codes.FailedPrecondition: perr.ExitConfig,
+		codes.Aborted:            perr.ExitTempFail,
+		codes.OutOfRange:         perr.ExitDataErr,
+		codes.Unimplemented:      perr.ExitSoftware,
+		codes.Internal:           perr.ExitSoftware,
+		codes.Unavailable:        perr.ExitUnavailable,
+		codes.DataLoss:           perr.ExitIOErr,
+		codes.Unauthenticated:    perr.ExitNoPerm,
+		codes.Code(42):           perr.ExitSoftware,
+	}
+
+	for code, want := range tests {
+		t.Run(code.String(), func(t *testing.T) {
+			if got := perr.ExitCodeOf(code); got != want {
+				t.Errorf("ExitCodeOf(%v) = %d, want %d", code, got, want)
+			}
+		})
+	}
+}
+
+func TestExitSignal(t *testing.T) {
+	tests := []struct {
+		sig  os.Signal
+		want int
+	}{
+		{sig: os.Interrupt, want: 130},
+		{sig: syscall.SIGTERM, want: 143},
+		{sig: syscall.SIGHUP, want: 129},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sig.String(), func(t *testing.T) {
+			if got := perr.ExitSignal(tt.sig); got != tt.want {
+				t.Errorf("ExitSignal(%v) = %d, want %d", tt.sig, got, tt.want)
+			}
+		})
+	}
+}
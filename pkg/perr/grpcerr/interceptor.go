@@ -0,0 +1,80 @@
+package grpcerr
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor translates errors returned by handlers to gRPC statuses with PublicStatus.
+func (i *Interceptor) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, i.PublicStatus(ctx, info.FullMethod, err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor translates errors returned by stream handlers to gRPC statuses with PublicStatus.
+func (i *Interceptor) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return i.PublicStatus(ss.Context(), info.FullMethod, err).Err()
+		}
+
+		return nil
+	}
+}
+
+/*
+UnaryServerInterceptor translates errors returned by handlers to gRPC statuses using the default interceptor,
+which logs with slog.Default(). Use NewInterceptor to log with another logger:
+
+	grpc.ChainUnaryInterceptor(grpcerr.NewInterceptor().WithLogger(log).UnaryServerInterceptor())
+*/
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return defaultInterceptor.UnaryServerInterceptor()
+}
+
+// StreamServerInterceptor translates errors returned by stream handlers to gRPC statuses using the default interceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return defaultInterceptor.StreamServerInterceptor()
+}
+
+// UnaryClientInterceptor translates statuses returned by calls back to *perr.Err with FromError.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor translates statuses returned by stream calls back to *perr.Err with FromError.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return FromError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return FromError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return FromError(s.ClientStream.CloseSend())
+}
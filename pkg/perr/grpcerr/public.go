@@ -0,0 +1,81 @@
+package grpcerr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+)
+
+// RequestIDKey is the metadata key the correlation ID is taken from.
+const RequestIDKey = "x-request-id"
+
+// Interceptor translates errors of handlers to public gRPC statuses, see PublicStatus.
+type Interceptor struct {
+	logger *slog.Logger
+}
+
+func NewInterceptor() *Interceptor {
+	return &Interceptor{
+		logger: slog.Default(),
+	}
+}
+
+// WithLogger sets the logger hidden errors are logged with.
+func (i *Interceptor) WithLogger(logger *slog.Logger) *Interceptor {
+	i.logger = logger
+
+	return i
+}
+
+/*
+PublicStatus converts err with ToStatus and hides messages of Internal and Unknown errors
+
+Hidden errors get perr.GenericMessage and a RequestInfo detail with the correlation ID taken from
+x-request-id metadata or generated, and are logged with every detail hidden from the client
+*/
+func (i *Interceptor) PublicStatus(ctx context.Context, method string, err error) *status.Status {
+	st := ToStatus(err)
+	if st == nil || !perr.IsHiddenCode(FromGRPCCode(st.Code())) {
+		return st
+	}
+
+	correlationID := requestID(ctx)
+
+	i.logger.Error("rpc failed",
+		slog.String("method", method),
+		slog.String("correlation_id", correlationID),
+		slog.String("error", fmt.Sprintf("%+v", err)),
+	)
+
+	public := status.New(st.Code(), perr.GenericMessage)
+
+	withDetails, derr := public.WithDetails(&errdetails.RequestInfo{RequestId: correlationID})
+	if derr != nil {
+		return public
+	}
+
+	return withDetails
+}
+
+var defaultInterceptor = NewInterceptor()
+
+// PublicStatus hides messages of Internal and Unknown errors using the default interceptor, see Interceptor.PublicStatus.
+func PublicStatus(ctx context.Context, method string, err error) *status.Status {
+	return defaultInterceptor.PublicStatus(ctx, method, err)
+}
+
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	return perr.NewCorrelationID()
+}
@@ -0,0 +1,233 @@
+package grpcerr
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+// ToGRPCCode converts the code to a gRPC one.
+func ToGRPCCode(c codes.Code) grpccodes.Code {
+	return grpccodes.Code(c)
+}
+
+// FromGRPCCode converts a gRPC code to the code.
+func FromGRPCCode(c grpccodes.Code) codes.Code {
+	return codes.Code(c)
+}
+
+/*
+ToStatus converts err to a gRPC status
+
+*validate.Error found in the chain of err is converted with a BadRequest detail, see validate.ErrorWithDetails.Status,
+*perr.Err is converted with its code, message without the cause and details,
+errors already carrying a status are returned as is, standard errors known to perr.CodeOf are mapped to their codes,
+anything else becomes Unknown
+*/
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	if ve := validate.ToValidationError(err); ve != nil {
+		if st, err := ve.ErrorWithDetails().Status(); err == nil {
+			return status.FromProto(st)
+		}
+	}
+
+	var ce *perr.Err
+	if errors.As(err, &ce) {
+		return fromCommonError(ce)
+	}
+
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	if perr.CodeOf(err) != codes.Unknown {
+		return fromCommonError(perr.Convert(err))
+	}
+
+	return status.New(grpccodes.Unknown, err.Error())
+}
+
+// DefinitionDomain is the domain of ErrorInfo details carrying IDs of defined errors as their reasons, see perr.Define.
+const DefinitionDomain = "perr.definition"
+
+/*
+FromStatus converts a gRPC status to *perr.Err, restoring details it knows about
+
+The ID of a defined error, passed as the reason of an ErrorInfo with DefinitionDomain, is restored as the ID
+of the error, so Definition.Is matches it. It returns nil for OK status.
+*/
+func FromStatus(st *status.Status) *perr.Err {
+	if st == nil || st.Code() == grpccodes.OK {
+		return nil
+	}
+
+	var details []perr.Detail
+
+	id := ""
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && id == "" && isDefinitionID(info) {
+			id = info.GetReason()
+
+			continue
+		}
+
+		if d := fromProtoDetail(detail); d != nil {
+			details = append(details, d)
+		}
+	}
+
+	e := perr.NewWithID(id, FromGRPCCode(st.Code()), st.Message())
+	for _, d := range details {
+		e = e.WithDetail(d)
+	}
+
+	return e
+}
+
+// isDefinitionID reports whether the ErrorInfo carries the ID of a defined error, see fromCommonError.
+func isDefinitionID(info *errdetails.ErrorInfo) bool {
+	return info.GetDomain() == DefinitionDomain && info.GetReason() != ""
+}
+
+// FromError converts an error returned by a gRPC call to *perr.Err.
+// Errors without a status are returned as is.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	if e := FromStatus(st); e != nil {
+		return e
+	}
+
+	return nil
+}
+
+func fromCommonError(e *perr.Err) *status.Status {
+	st := status.New(ToGRPCCode(e.Code()), e.Message())
+
+	details := make([]protoadapt.MessageV1, 0, len(e.Details())+1)
+	for _, detail := range e.Details() {
+		if msg := toProtoDetail(detail); msg != nil {
+			details = append(details, msg)
+		}
+	}
+
+	// the ID of a defined error is passed as the reason, unless the error has its own one
+	if _, ok := perr.DetailOf[*perr.ErrorInfo](e); !ok && e.ID() != "" {
+		details = append(details, &errdetails.ErrorInfo{Reason: e.ID(), Domain: DefinitionDomain})
+	}
+
+	if len(details) == 0 {
+		return st
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+func toProtoDetail(detail perr.Detail) protoadapt.MessageV1 {
+	switch d := detail.(type) {
+	case *perr.BadRequest:
+		msg := &errdetails.BadRequest{}
+		for _, v := range d.FieldViolations {
+			msg.FieldViolations = append(msg.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Description,
+			})
+		}
+
+		return msg
+	case *perr.RetryInfo:
+		return &errdetails.RetryInfo{RetryDelay: durationpb.New(d.RetryDelay)}
+	case *perr.ResourceInfo:
+		return &errdetails.ResourceInfo{
+			ResourceType: d.ResourceType,
+			ResourceName: d.ResourceName,
+			Owner:        d.Owner,
+			Description:  d.Description,
+		}
+	case *perr.QuotaFailure:
+		msg := &errdetails.QuotaFailure{}
+		for _, v := range d.Violations {
+			msg.Violations = append(msg.Violations, &errdetails.QuotaFailure_Violation{
+				Subject:     v.Subject,
+				Description: v.Description,
+			})
+		}
+
+		return msg
+	case *perr.ErrorInfo:
+		return &errdetails.ErrorInfo{
+			Reason:   d.Reason,
+			Domain:   d.Domain,
+			Metadata: d.Metadata,
+		}
+	default:
+		return nil
+	}
+}
+
+func fromProtoDetail(detail any) perr.Detail {
+	switch d := detail.(type) {
+	case *errdetails.BadRequest:
+		br := &perr.BadRequest{}
+		for _, v := range d.GetFieldViolations() {
+			br.FieldViolations = append(br.FieldViolations, perr.FieldViolation{
+				Field:       v.GetField(),
+				Description: v.GetDescription(),
+			})
+		}
+
+		return br
+	case *errdetails.RetryInfo:
+		return &perr.RetryInfo{RetryDelay: d.GetRetryDelay().AsDuration()}
+	case *errdetails.ResourceInfo:
+		return &perr.ResourceInfo{
+			ResourceType: d.GetResourceType(),
+			ResourceName: d.GetResourceName(),
+			Owner:        d.GetOwner(),
+			Description:  d.GetDescription(),
+		}
+	case *errdetails.QuotaFailure:
+		qf := &perr.QuotaFailure{}
+		for _, v := range d.GetViolations() {
+			qf.Violations = append(qf.Violations, perr.QuotaViolation{
+				Subject:     v.GetSubject(),
+				Description: v.GetDescription(),
+			})
+		}
+
+		return qf
+	case *errdetails.ErrorInfo:
+		return &perr.ErrorInfo{
+			Reason:   d.GetReason(),
+			Domain:   d.GetDomain(),
+			Metadata: d.GetMetadata(),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+package grpcerr_test
+
+import (
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/grpcerr"
+)
+
+var errOrderNotFound = perr.Define("grpcerr_test.order_not_found", codes.NotFound, "order %s not found")
+
+func TestStatusRoundTripID(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *perr.Err
+		wantID string
+		reason string
+	}{
+		{
+			name:   "defined",
+			err:    errOrderNotFound.New("42").WithResource("order", "42"),
+			wantID: errOrderNotFound.ID(),
+		},
+		{
+			name:   "own reason",
+			err:    perr.New("out of stock", codes.FailedPrecondition).WithReason("STOCK_DEPLETED", "orders.example.com"),
+			reason: "STOCK_DEPLETED",
+		},
+		{
+			name:   "own reason without a domain",
+			err:    perr.New("out of stock", codes.FailedPrecondition).WithReason("STOCK_DEPLETED", ""),
+			reason: "STOCK_DEPLETED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := grpcerr.FromStatus(grpcerr.ToStatus(tt.err))
+
+			if got.ID() != tt.wantID {
+				t.Errorf("ID() = %q, want %q", got.ID(), tt.wantID)
+			}
+
+			if got.Code() != tt.err.Code() || got.Message() != tt.err.Message() {
+				t.Errorf("FromStatus() = %v %q, want %v %q", got.Code(), got.Message(), tt.err.Code(), tt.err.Message())
+			}
+
+			if len(got.Details()) != len(tt.err.Details()) {
+				t.Errorf("details = %+v, want %+v", got.Details(), tt.err.Details())
+			}
+
+			if info, ok := perr.DetailOf[*perr.ErrorInfo](got); tt.reason != "" && (!ok || info.Reason != tt.reason) {
+				t.Errorf("ErrorInfo = %+v, want reason %q", info, tt.reason)
+			}
+		})
+	}
+
+	if !errOrderNotFound.Is(grpcerr.FromStatus(grpcerr.ToStatus(errOrderNotFound.New("1")))) {
+		t.Error("Is() = false after a round trip, want true")
+	}
+}
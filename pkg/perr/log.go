@@ -0,0 +1,203 @@
+package perr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+// LogValue implements slog.LogValuer: the error is logged as a group with its code, message, causes, details and stack.
+func (e *Err) LogValue() slog.Value {
+	return slog.GroupValue(append([]slog.Attr{slog.String("message", e.msg)}, e.logAttrs(causeChain(e.cause))...)...)
+}
+
+// logAttrs returns attributes of the error logged next to a message, causes are messages of the errors it wraps.
+func (e *Err) logAttrs(causes []string) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Any("code", e.code),
+	}
+
+	if e.id != "" {
+		attrs = append(attrs, slog.String("id", e.id))
+	}
+
+	if e.internal != "" {
+		attrs = append(attrs, slog.String("internal", e.internal))
+	}
+
+	if len(causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+
+	if len(e.details) > 0 {
+		attrs = append(attrs, slog.Any("details", e.details))
+	}
+
+	if frames := e.stack.Frames(); len(frames) > 0 {
+		stack := make([]string, 0, len(frames))
+		for _, frame := range frames {
+			stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		}
+
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+
+	return attrs
+}
+
+// causeChain returns messages of err and every error it wraps, *Err contributing only its own message.
+func causeChain(err error) []string {
+	var chain []string
+
+	for err != nil {
+		var e *Err
+		if errors.As(err, &e) && e == err {
+			chain = append(chain, e.msg)
+		} else {
+			chain = append(chain, err.Error())
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return chain
+}
+
+/*
+LogHandler is a slog.Handler middleware expanding error attributes
+
+Any attribute holding an error is logged as a group with its message, like Err.LogValue does. The level of the record
+is raised by errors with an explicit code, of *Err in the chain or of a gRPC status: to Warn for client-side codes
+and to Error for server-side ones (see codes.HTTPStatus). Errors bound with Logger.With raise levels of every record.
+*/
+type LogHandler struct {
+	next slog.Handler
+
+	// floor is the level raised by bound errors, if raised is set
+	floor  slog.Level
+	raised bool
+}
+
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+// Enabled reports true if the next handler accepts the level or errors, since the level may be raised in Handle.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level) || h.next.Enabled(ctx, slog.LevelError)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := r.Level
+	if h.raised {
+		level = max(level, h.floor)
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded, code, ok := expandError(attr)
+		if ok && code != codes.OK {
+			level = max(level, levelOf(code))
+		}
+
+		attrs = append(attrs, expanded)
+
+		return true
+	})
+
+	if !h.next.Enabled(ctx, level) {
+		return nil
+	}
+
+	nr := slog.NewRecord(r.Time, level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+
+	return h.next.Handle(ctx, nr)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		a, code, ok := expandError(attr)
+		if ok && code != codes.OK && (!c.raised || levelOf(code) > c.floor) {
+			c.floor, c.raised = levelOf(code), true
+		}
+
+		expanded = append(expanded, a)
+	}
+
+	c.next = h.next.WithAttrs(expanded)
+
+	return &c
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	c := *h
+	c.next = h.next.WithGroup(name)
+
+	return &c
+}
+
+// expandError returns the attribute holding an error as a group and the explicit code of the error, if it has one.
+func expandError(attr slog.Attr) (slog.Attr, codes.Code, bool) {
+	if kind := attr.Value.Kind(); kind != slog.KindAny && kind != slog.KindLogValuer {
+		return attr, codes.OK, false
+	}
+
+	err, ok := attr.Value.Any().(error)
+	if !ok || err == nil {
+		return attr, codes.OK, false
+	}
+
+	var e *Err
+	if errors.As(err, &e) {
+		if e == err {
+			return slog.Attr{Key: attr.Key, Value: e.LogValue()}, e.code, true
+		}
+
+		// the message of the wrapping error keeps its context, e.g. "load user 42: user not found"
+		attrs := append([]slog.Attr{slog.String("message", err.Error())}, e.logAttrs(causeChain(errors.Unwrap(err)))...)
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}, e.code, true
+	}
+
+	attrs := []slog.Attr{slog.String("message", err.Error())}
+
+	code, explicit := grpcCode(err)
+	if explicit {
+		attrs = append(attrs, slog.Any("code", code))
+	}
+
+	if causes := causeChain(errors.Unwrap(err)); len(causes) > 0 {
+		attrs = append(attrs, slog.Any("causes", causes))
+	}
+
+	return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}, code, explicit
+}
+
+// grpcCode returns the code of a gRPC status error in the chain of err, codes are the same as of gRPC.
+func grpcCode(err error) (codes.Code, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return codes.OK, false
+	}
+
+	return codes.Code(st.Code()), st.Code() != grpccodes.OK
+}
+
+func levelOf(c codes.Code) slog.Level {
+	if codes.HTTPStatus(c) >= 500 {
+		return slog.LevelError
+	}
+
+	return slog.LevelWarn
+}
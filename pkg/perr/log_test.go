@@ -0,0 +1,108 @@
+package perr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+func TestLogHandler(t *testing.T) {
+	notFound := perr.New("user not found", codes.NotFound)
+
+	tests := []struct {
+		name    string
+		log     func(l *slog.Logger)
+		level   string
+		message string
+		code    any
+	}{
+		{
+			name:    "perr error",
+			log:     func(l *slog.Logger) { l.Info("failed", "err", perr.New("query failed", codes.Internal)) },
+			level:   "ERROR",
+			message: "query failed",
+			code:    "INTERNAL",
+		},
+		{
+			name:    "wrapped perr error",
+			log:     func(l *slog.Logger) { l.Info("failed", "err", fmt.Errorf("load user 42: %w", notFound)) },
+			level:   "WARN",
+			message: "load user 42: user not found",
+			code:    "NOT_FOUND",
+		},
+		{
+			name:    "plain error",
+			log:     func(l *slog.Logger) { l.Debug("retrying", "err", io.ErrUnexpectedEOF) },
+			level:   "DEBUG",
+			message: io.ErrUnexpectedEOF.Error(),
+		},
+		{
+			name:    "grpc status",
+			log:     func(l *slog.Logger) { l.Info("failed", "err", status.Error(grpccodes.Unavailable, "no backends")) },
+			level:   "ERROR",
+			message: "rpc error: code = Unavailable desc = no backends",
+			code:    "UNAVAILABLE",
+		},
+		{
+			name:    "bound error",
+			log:     func(l *slog.Logger) { l.With("err", notFound).Debug("skipped") },
+			level:   "WARN",
+			message: "user not found",
+			code:    "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			tt.log(slog.New(perr.NewLogHandler(slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+			var record struct {
+				Level string         `json:"level"`
+				Err   map[string]any `json:"err"`
+			}
+
+			if err := json.Unmarshal(b.Bytes(), &record); err != nil {
+				t.Fatalf("record %q: %v", b.String(), err)
+			}
+
+			if record.Level != tt.level {
+				t.Errorf("level = %s, want %s", record.Level, tt.level)
+			}
+
+			if record.Err["message"] != tt.message {
+				t.Errorf("message = %v, want %q", record.Err["message"], tt.message)
+			}
+
+			if record.Err["code"] != tt.code {
+				t.Errorf("code = %v, want %v", record.Err["code"], tt.code)
+			}
+		})
+	}
+}
+
+func TestLogHandlerDropsBelowLevel(t *testing.T) {
+	var b bytes.Buffer
+
+	logger := slog.New(perr.NewLogHandler(slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	logger.Debug("retrying", "err", io.ErrUnexpectedEOF)
+	if b.Len() != 0 {
+		t.Errorf("record = %s, want a plain error to keep the level", b.String())
+	}
+
+	logger.Debug("retrying", "err", perr.New("no backends", codes.Unavailable))
+	if b.Len() == 0 {
+		t.Error("no record, want the level raised by the code of the error")
+	}
+}
@@ -0,0 +1,169 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+// ContentType is the media type of RFC 7807 problem details.
+const ContentType = "application/problem+json"
+
+// RequestIDHeader is the header the correlation ID is taken from and returned in.
+const RequestIDHeader = "X-Request-ID"
+
+// Problem is an RFC 7807 problem details body extended with the error code, details and validation messages.
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Instance string        `json:"instance,omitempty"`
+	Code     codes.Code    `json:"code"`
+	ID       string        `json:"id,omitempty"`
+	Details  []perr.Detail `json:"details,omitempty"` // tagged with "@type" like google.rpc JSON
+	Errors   []string      `json:"errors,omitempty"`
+
+	FieldViolations []validate.FieldViolation `json:"field_violations,omitempty"`
+
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// FromError builds a problem from err.
+// *validate.Error and *perr.Err are found anywhere in the chain of err, anything else is converted with perr.Convert.
+// Only public messages get into the problem, see perr.PublicMessage, and details only for codes that aren't hidden.
+func FromError(err error) *Problem {
+	var ve *validate.Error
+	if errors.As(err, &ve) {
+		ed := ve.ErrorWithDetails()
+
+		p := newProblem(ed.Code, ed.Message)
+		p.Errors = ed.Details
+		p.FieldViolations = ed.FieldViolations
+
+		return p
+	}
+
+	var ce *perr.Err
+	if errors.As(err, &ce) {
+		p := newProblem(ce.Code(), perr.PublicMessage(ce))
+		p.ID = ce.ID()
+
+		// details of hidden errors carry internals like queries, PublicStatus of grpcerr drops them too
+		if !perr.IsHiddenCode(ce.Code()) {
+			p.Details = ce.Details()
+		}
+
+		return p
+	}
+
+	ce = perr.Convert(err)
+
+	return newProblem(ce.Code(), perr.PublicMessage(ce))
+}
+
+func newProblem(code codes.Code, detail string) *Problem {
+	status := codes.HTTPStatus(code)
+
+	return &Problem{
+		Type:   "about:blank",
+		Title:  statusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func statusText(status int) string {
+	if status == codes.StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+
+	return http.StatusText(status)
+}
+
+// HandlerFunc is an HTTP handler returning an error, which is rendered as a problem.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Writer renders errors as application/problem+json responses.
+type Writer struct {
+	logger *slog.Logger
+}
+
+func NewWriter() *Writer {
+	return &Writer{
+		logger: slog.Default(),
+	}
+}
+
+func (wr *Writer) WithLogger(logger *slog.Logger) *Writer {
+	wr.logger = logger
+
+	return wr
+}
+
+/*
+Write renders err as a problem response
+
+Errors with server-side codes get a correlation ID taken from the X-Request-ID header or generated,
+and are logged with every detail hidden from the client
+*/
+func (wr *Writer) Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := FromError(err)
+	p.Instance = r.URL.Path
+
+	if p.Status >= http.StatusInternalServerError {
+		p.CorrelationID = r.Header.Get(RequestIDHeader)
+		if p.CorrelationID == "" {
+			p.CorrelationID = perr.NewCorrelationID()
+		}
+
+		w.Header().Set(RequestIDHeader, p.CorrelationID)
+
+		wr.logger.Error("request failed",
+			slog.String("path", r.URL.Path),
+			slog.String("correlation_id", p.CorrelationID),
+			slog.String("error", fmt.Sprintf("%+v", err)),
+		)
+	}
+
+	body, merr := json.Marshal(p)
+	if merr != nil {
+		http.Error(w, merr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+
+	_, _ = w.Write(body)
+}
+
+// Handler adapts fn to http.Handler, rendering a returned error as a problem.
+func (wr *Writer) Handler(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			wr.Write(w, r, err)
+		}
+	})
+}
+
+var defaultWriter = NewWriter()
+
+// Write renders err as a problem response using the default writer.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	defaultWriter.Write(w, r, err)
+}
+
+// ServeHTTP implements http.Handler, rendering a returned error as a problem using the default writer.
+func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := fn(w, r); err != nil {
+		defaultWriter.Write(w, r, err)
+	}
+}
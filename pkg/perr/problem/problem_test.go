@@ -0,0 +1,141 @@
+package problem_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/problem"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+var writer = problem.NewWriter().WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+// write renders err with the writer and decodes the response body.
+func write(t *testing.T, r *http.Request, err error) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	writer.Write(rec, r, err)
+
+	var body map[string]any
+	if jerr := json.Unmarshal(rec.Body.Bytes(), &body); jerr != nil {
+		t.Fatalf("body %q: %v", rec.Body.String(), jerr)
+	}
+
+	return rec, body
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string
+	}{
+		{name: "perr error", err: perr.New("user not found", codes.NotFound), status: http.StatusNotFound, code: "NOT_FOUND", detail: "user not found"},
+		{name: "wrapped perr error", err: fmt.Errorf("get user: %w", perr.New("user exists", codes.AlreadyExists)), status: http.StatusConflict, code: "ALREADY_EXISTS", detail: "user exists"},
+		{name: "standard error", err: context.DeadlineExceeded, status: http.StatusGatewayTimeout, code: "DEADLINE_EXCEEDED", detail: "deadline exceeded"},
+		{name: "canceled", err: context.Canceled, status: codes.StatusClientClosedRequest, code: "CANCELLED", detail: "canceled"},
+		{name: "plain error", err: errors.New("pq: connection refused"), status: http.StatusInternalServerError, code: "UNKNOWN", detail: perr.GenericMessage},
+		{name: "validation error", err: validate.NewError("name is required"), status: http.StatusBadRequest, code: "INVALID_ARGUMENT", detail: "bad validation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := write(t, httptest.NewRequest(http.MethodGet, "/users/1", nil), tt.err)
+
+			if got := rec.Header().Get("Content-Type"); got != problem.ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, problem.ContentType)
+			}
+
+			if rec.Code != tt.status || body["status"] != float64(tt.status) {
+				t.Errorf("status = %d, body status = %v, want %d", rec.Code, body["status"], tt.status)
+			}
+
+			if body["title"] == "" {
+				t.Error("title is empty")
+			}
+
+			if body["code"] != tt.code || body["detail"] != tt.detail || body["instance"] != "/users/1" {
+				t.Errorf("body = %v, want code %s and detail %q of /users/1", body, tt.code, tt.detail)
+			}
+		})
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	err := validate.NewFieldError(validate.FieldViolation{Field: "Name", JSONPath: "name", Rule: "required", Message: "name is required"})
+
+	p := problem.FromError(fmt.Errorf("create user: %w", err))
+
+	if !slices.Equal(p.Errors, []string{"name is required"}) {
+		t.Errorf("Errors = %v, want [name is required]", p.Errors)
+	}
+
+	if len(p.FieldViolations) != 1 || p.FieldViolations[0].JSONPath != "name" {
+		t.Errorf("FieldViolations = %+v, want one of name", p.FieldViolations)
+	}
+}
+
+func TestWriteDetails(t *testing.T) {
+	err := perr.New("user not found", codes.NotFound).WithResource("user", "42")
+
+	_, body := write(t, httptest.NewRequest(http.MethodGet, "/users/42", nil), err)
+
+	details, _ := body["details"].([]any)
+	if len(details) != 1 {
+		t.Fatalf("details = %v, want one", body["details"])
+	}
+
+	detail, _ := details[0].(map[string]any)
+	if detail["@type"] != "type.googleapis.com/google.rpc.ResourceInfo" || detail["resourceName"] != "42" {
+		t.Errorf("detail = %v, want ResourceInfo tagged with @type", detail)
+	}
+}
+
+func TestWriteCorrelationID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set(problem.RequestIDHeader, "req-1")
+
+	rec, body := write(t, r, perr.New("query failed", codes.Internal))
+
+	if body["correlation_id"] != "req-1" || rec.Header().Get(problem.RequestIDHeader) != "req-1" {
+		t.Errorf("correlation_id = %v, header = %q, want req-1", body["correlation_id"], rec.Header().Get(problem.RequestIDHeader))
+	}
+
+	_, body = write(t, httptest.NewRequest(http.MethodGet, "/users", nil), perr.New("bad id", codes.InvalidArgument))
+	if _, ok := body["correlation_id"]; ok {
+		t.Errorf("correlation_id = %v, want none for client errors", body["correlation_id"])
+	}
+}
+
+func TestWriteHidesDetails(t *testing.T) {
+	err := perr.New("query failed", codes.Internal).
+		WithReason("DB_FAILED", "").
+		WithMetadata("sql", "SELECT * FROM users")
+
+	rec, body := write(t, httptest.NewRequest(http.MethodGet, "/users", nil), err)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	if body["detail"] != perr.GenericMessage {
+		t.Errorf("detail = %v, want %q", body["detail"], perr.GenericMessage)
+	}
+
+	if details, ok := body["details"]; ok {
+		t.Errorf("details = %v, want none", details)
+	}
+}
@@ -0,0 +1,53 @@
+package perr
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+// GenericMessage replaces messages of Internal and Unknown errors shown to clients.
+const GenericMessage = "internal error"
+
+// WithInternal returns a copy of the error with a debug message that is logged, but never shown to clients.
+func (e *Err) WithInternal(msg string) *Err {
+	c := e.clone()
+	c.internal = msg
+
+	return c
+}
+
+// Internal returns the debug message set by WithInternal.
+func (e *Err) Internal() string {
+	return e.internal
+}
+
+/*
+PublicMessage returns the message of err that is safe to show to clients
+
+It is the message of the first *Err in the chain without its cause and internal message.
+Errors with Internal or Unknown code and errors without *Err in the chain get GenericMessage
+*/
+func PublicMessage(err error) string {
+	var e *Err
+	if !errors.As(err, &e) || IsHiddenCode(e.code) {
+		return GenericMessage
+	}
+
+	return e.msg
+}
+
+// IsHiddenCode reports whether messages of errors with the code must not be shown to clients.
+func IsHiddenCode(c codes.Code) bool {
+	return c == codes.Internal || c == codes.Unknown
+}
+
+// NewCorrelationID returns a random ID to match an error shown to a client with its log record.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
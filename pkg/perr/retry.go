@@ -0,0 +1,199 @@
+package perr
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+type retryMark struct {
+	err       error
+	retryable bool
+}
+
+func (m *retryMark) Error() string {
+	return m.err.Error()
+}
+
+func (m *retryMark) Unwrap() error {
+	return m.err
+}
+
+// Retryable marks err as retryable regardless of its code.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryMark{err: err, retryable: true}
+}
+
+// Permanent marks err as not retryable regardless of its code.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryMark{err: err, retryable: false}
+}
+
+/*
+IsRetryable reports whether the operation failed with err may succeed if retried
+
+The first match wins:
+  - errors marked with Retryable or Permanent
+  - context.Canceled is permanent, context.DeadlineExceeded is retryable
+  - errors with code Unavailable, Aborted, ResourceExhausted or DeadlineExceeded are retryable
+  - errors implementing Temporary() bool, like net.Error, report it themselves
+
+Anything else is permanent.
+*/
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mark *retryMark
+	if errors.As(err, &mark) {
+		return mark.retryable
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var ce *Err
+	if errors.As(err, &ce) {
+		return IsRetryableCode(ce.Code())
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		return temporary.Temporary()
+	}
+
+	return false
+}
+
+// IsRetryableCode reports whether errors with the code are transient.
+func IsRetryableCode(c codes.Code) bool {
+	switch c {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Retrier retries operations failing with retryable errors with exponential backoff and jitter.
+type Retrier struct {
+	attempts   int
+	initial    time.Duration
+	maxDelay   time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+// NewRetrier creates a retrier making up to 5 attempts with delays from 100ms up to 5s.
+func NewRetrier() *Retrier {
+	return &Retrier{
+		attempts:   5,
+		initial:    100 * time.Millisecond,
+		maxDelay:   5 * time.Second,
+		multiplier: 2,
+		jitter:     0.2,
+	}
+}
+
+// WithAttempts sets the maximum number of attempts, including the first one.
+func (r *Retrier) WithAttempts(attempts int) *Retrier {
+	r.attempts = attempts
+
+	return r
+}
+
+// WithBackoff sets the delay before the second attempt and the maximum delay between attempts.
+func (r *Retrier) WithBackoff(initial, maxDelay time.Duration) *Retrier {
+	r.initial = initial
+	r.maxDelay = maxDelay
+
+	return r
+}
+
+// WithMultiplier sets the factor the delay grows by after each attempt.
+func (r *Retrier) WithMultiplier(multiplier float64) *Retrier {
+	r.multiplier = multiplier
+
+	return r
+}
+
+// WithJitter sets the fraction of the delay randomized in both directions, e.g. 0.2 for ±20%.
+func (r *Retrier) WithJitter(jitter float64) *Retrier {
+	r.jitter = jitter
+
+	return r
+}
+
+/*
+Do calls fn until it succeeds, fails with an error that is not retryable, attempts run out or ctx is done
+
+Pass the root context of the closer, so retries stop on shutdown. A RetryInfo detail of the error overrides the backoff delay.
+The last error of fn is returned.
+*/
+func (r *Retrier) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	delay := r.initial
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt >= r.attempts || !IsRetryable(err) {
+			return err
+		}
+
+		wait := r.withJitter(delay)
+		if info, ok := DetailOf[*RetryInfo](err); ok && info.RetryDelay > 0 {
+			wait = info.RetryDelay
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+
+		delay = time.Duration(float64(delay) * r.multiplier)
+		if delay > r.maxDelay {
+			delay = r.maxDelay
+		}
+	}
+}
+
+func (r *Retrier) withJitter(d time.Duration) time.Duration {
+	if r.jitter <= 0 {
+		return d
+	}
+
+	return time.Duration(float64(d) * (1 + r.jitter*(2*rand.Float64()-1)))
+}
+
+var defaultRetrier = NewRetrier()
+
+// Retry calls fn with the default retrier, see Retrier.Do.
+func Retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return defaultRetrier.Do(ctx, fn)
+}
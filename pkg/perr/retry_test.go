@@ -0,0 +1,174 @@
+package perr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+type temporaryError struct {
+	temporary bool
+}
+
+func (e temporaryError) Error() string   { return "temporary" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+func TestIsRetryableCode(t *testing.T) {
+	retryable := map[codes.Code]bool{
+		codes.DeadlineExceeded:  true,
+		codes.ResourceExhausted: true,
+		codes.Aborted:           true,
+		codes.Unavailable:       true,
+	}
+
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		t.Run(c.String(), func(t *testing.T) {
+			if got := perr.IsRetryableCode(c); got != retryable[c] {
+				t.Errorf("IsRetryableCode(%v) = %v, want %v", c, got, retryable[c])
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	unavailable := perr.New("db is down", codes.Unavailable)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+		{name: "retryable code", err: unavailable, want: true},
+		{name: "wrapped retryable code", err: fmt.Errorf("query: %w", unavailable), want: true},
+		{name: "permanent code", err: errNotFound, want: false},
+		{name: "marked retryable", err: perr.Retryable(errors.New("boom")), want: true},
+		{name: "marked permanent", err: perr.Permanent(unavailable), want: false},
+		{name: "mark wins over context", err: perr.Retryable(context.Canceled), want: true},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "deadline", err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: true},
+		{name: "temporary", err: temporaryError{temporary: true}, want: true},
+		{name: "not temporary", err: temporaryError{temporary: false}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := perr.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetrierDo(t *testing.T) {
+	unavailable := perr.New("db is down", codes.Unavailable)
+
+	tests := []struct {
+		name      string
+		errs      []error
+		attempts  int
+		wantCalls int
+		wantErr   error
+		// minDelay is the lower bound of the total delay between attempts
+		minDelay time.Duration
+	}{
+		{
+			name:      "succeeds at once",
+			errs:      nil,
+			attempts:  3,
+			wantCalls: 1,
+		},
+		{
+			name:      "succeeds after retries",
+			errs:      []error{unavailable, unavailable},
+			attempts:  3,
+			wantCalls: 3,
+			minDelay:  10*time.Millisecond + 20*time.Millisecond,
+		},
+		{
+			name:      "stops on a permanent error",
+			errs:      []error{unavailable, errNotFound, unavailable},
+			attempts:  5,
+			wantCalls: 2,
+			wantErr:   errNotFound,
+		},
+		{
+			name:      "runs out of attempts",
+			errs:      []error{unavailable, unavailable, unavailable, unavailable},
+			attempts:  4,
+			wantCalls: 4,
+			wantErr:   unavailable,
+			// the delay grows from 10ms up to 25ms
+			minDelay: 10*time.Millisecond + 20*time.Millisecond + 25*time.Millisecond,
+		},
+		{
+			name:      "retry info overrides backoff",
+			errs:      []error{perr.New("slow down", codes.ResourceExhausted).WithRetryAfter(50 * time.Millisecond)},
+			attempts:  2,
+			wantCalls: 2,
+			minDelay:  50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := perr.NewRetrier().
+				WithAttempts(tt.attempts).
+				WithBackoff(10*time.Millisecond, 25*time.Millisecond).
+				WithMultiplier(2).
+				WithJitter(0)
+
+			calls := 0
+			start := time.Now()
+
+			err := r.Do(context.Background(), func(context.Context) error {
+				calls++
+				if calls > len(tt.errs) {
+					return nil
+				}
+
+				return tt.errs[calls-1]
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() = %v, want %v", err, tt.wantErr)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if elapsed := time.Since(start); elapsed < tt.minDelay {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.minDelay)
+			}
+		})
+	}
+}
+
+func TestRetrierDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	unavailable := perr.New("db is down", codes.Unavailable)
+	calls := 0
+
+	err := perr.NewRetrier().WithBackoff(time.Hour, time.Hour).Do(ctx, func(context.Context) error {
+		calls++
+
+		return unavailable
+	})
+
+	if !errors.Is(err, unavailable) {
+		t.Errorf("Do() = %v, want %v", err, unavailable)
+	}
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1, retries have to stop once ctx is done", calls)
+	}
+}
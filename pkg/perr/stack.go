@@ -0,0 +1,47 @@
+package perr
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+const maxStackDepth = 32
+
+// Stack is a stack trace captured by Err.WithStack.
+type Stack []uintptr
+
+func callers(skip int) Stack {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+
+	return pcs[:n]
+}
+
+// Frames returns the frames of the stack trace.
+func (s Stack) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(s)
+	result := make([]runtime.Frame, 0, len(s))
+
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+
+		if !more {
+			return result
+		}
+	}
+}
+
+// Format prints a frame per line as function name followed by file and line.
+func (s Stack) Format(st fmt.State, _ rune) {
+	_, _ = io.WriteString(st, "stack:")
+
+	for _, frame := range s.Frames() {
+		_, _ = fmt.Fprintf(st, "\n  %s\n    %s:%d", frame.Function, frame.File, frame.Line)
+	}
+}
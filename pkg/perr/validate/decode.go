@@ -0,0 +1,326 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/defany/platcom/v2/pkg/internal/reflectx"
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+)
+
+// DefaultMaxBodySize is the limit of request bodies read by Decode.
+const DefaultMaxBodySize = 1 << 20
+
+// Rules of violations reported by Decode for fields which can't be decoded.
+const (
+	RuleUnknownField = "unknown_field"
+	RuleInvalidType  = "invalid_type"
+)
+
+// Decoder decodes and validates requests, configure it once before sharing.
+type Decoder struct {
+	maxBodySize int64
+	validator   *Validator
+}
+
+// NewDecoder creates a decoder with DefaultMaxBodySize and the default validator.
+func NewDecoder() *Decoder {
+	return &Decoder{
+		maxBodySize: DefaultMaxBodySize,
+		validator:   defaultValidator,
+	}
+}
+
+// WithMaxBodySize sets the limit of request bodies in bytes.
+func (d *Decoder) WithMaxBodySize(size int64) *Decoder {
+	d.maxBodySize = size
+
+	return d
+}
+
+// WithValidator sets the validator decoded values are validated with.
+func (d *Decoder) WithValidator(v *Validator) *Decoder {
+	d.validator = v
+
+	return d
+}
+
+var defaultDecoder = NewDecoder()
+
+/*
+Decode decodes the request into T with the default decoder and validates it, see DecodeWith
+
+	func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+		req, err := validate.Decode[CreateUserRequest](r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+		...
+	}
+*/
+func Decode[T any](r *http.Request) (T, error) {
+	return DecodeWith[T](defaultDecoder, r)
+}
+
+/*
+DecodeWith decodes the request into T and validates it with the validator of the decoder
+
+Sources are read in the order:
+  - the body: JSON by json tags, or an urlencoded or multipart form by form tags, falling back to json names
+  - path values of http.ServeMux patterns by path tags
+  - query parameters by query tags
+
+Unknown fields of the body are rejected, unknown query parameters too if T has fields with query tags.
+Fields which can't be decoded and failures of validation are returned as *Error,
+a malformed or too large body as *perr.Err with codes.InvalidArgument.
+Messages are in the locale picked by LocaleFromRequest.
+*/
+func DecodeWith[T any](d *Decoder, r *http.Request) (T, error) {
+	var v T
+
+	rv := reflect.ValueOf(&v).Elem()
+	for rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return v, fmt.Errorf("validate: can't decode into %T, a struct is required", v)
+	}
+
+	locale := LocaleFromRequest(r)
+	s := &decoding{locale: normalizeLocale(locale), e: &Error{}}
+
+	if err := d.decodeBody(r, rv, s); err != nil {
+		return v, err
+	}
+
+	s.decodeValues(rv, "path", pathValues(r, rv), false)
+	s.decodeValues(rv, "query", r.URL.Query(), true)
+
+	if len(s.e.Messages) > 0 {
+		return v, s.e
+	}
+
+	ctx := perr.ContextWithLocale(r.Context(), locale)
+
+	return v, d.validator.ValidateContext(ctx, rv.Addr().Interface())
+}
+
+// decoding collects violations of fields which can't be decoded, with messages in the locale.
+type decoding struct {
+	locale string
+	e      *Error
+}
+
+func (s *decoding) violation(field, name, rule string) {
+	messages, ok := catalogue(s.locale)
+	if _, found := messages[rule]; !ok || !found {
+		messages, _ = catalogue(DefaultLocale)
+	}
+
+	s.e.addViolation(FieldViolation{
+		Field:    field,
+		JSONPath: name,
+		Rule:     rule,
+		Message:  strings.ReplaceAll(messages[rule], "{field}", name),
+	})
+}
+
+func (d *Decoder) decodeBody(r *http.Request, rv reflect.Value, s *decoding) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, d.maxBodySize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return bodyError(err)
+		}
+
+		s.decodeValues(rv, "form", r.PostForm, true)
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(d.maxBodySize); err != nil {
+			return bodyError(err)
+		}
+
+		s.decodeValues(rv, "form", r.MultipartForm.Value, true)
+	case mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return s.decodeJSON(r.Body, rv)
+	default:
+		return perr.New(fmt.Sprintf("unsupported content type %q", mediaType), codes.InvalidArgument)
+	}
+
+	return nil
+}
+
+func (s *decoding) decodeJSON(body io.Reader, rv reflect.Value) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(rv.Addr().Interface())
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	// a mismatch of the whole body, e.g. an array for a struct, has no field to blame
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		s.violation(typeErr.Field, typeErr.Field, RuleInvalidType)
+
+		return nil
+	}
+
+	if name, ok := unknownJSONField(err); ok {
+		s.violation(name, name, RuleUnknownField)
+
+		return nil
+	}
+
+	if err != nil {
+		return bodyError(err)
+	}
+
+	if dec.More() {
+		return perr.New("request body must contain a single JSON value", codes.InvalidArgument)
+	}
+
+	return nil
+}
+
+// unknownJSONField extracts the name from errors of json.Decoder.DisallowUnknownFields, it has no error type for them.
+func unknownJSONField(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+
+	quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return "", false
+	}
+
+	name, err := strconv.Unquote(quoted)
+
+	return name, err == nil
+}
+
+func bodyError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return perr.Wrap(err, codes.InvalidArgument, fmt.Sprintf("request body is larger than %d bytes", maxErr.Limit))
+	}
+
+	return perr.Wrap(err, codes.InvalidArgument, "malformed request body")
+}
+
+// pathValues collects values of path tags of the struct from path values of the request.
+func pathValues(r *http.Request, rv reflect.Value) url.Values {
+	values := make(url.Values)
+
+	for name := range taggedFields(rv, "path") {
+		if value := r.PathValue(name); value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	return values
+}
+
+// decodeValues sets fields of rv named by the tag from values, values without a field are violations if strict,
+// unless the struct has no fields named by the tag at all.
+func (s *decoding) decodeValues(rv reflect.Value, tag string, values map[string][]string, strict bool) {
+	fields := taggedFields(rv, tag)
+
+	// e.g. tracking parameters of a request with a JSON body aren't expected by the struct, but aren't mistakes either
+	strict = strict && len(fields) > 0
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		raw := values[name]
+
+		field, ok := fields[name]
+		if !ok {
+			if strict {
+				s.violation(name, name, RuleUnknownField)
+			}
+
+			continue
+		}
+
+		fv, err := rv.FieldByIndexErr(field.Index)
+		if err != nil {
+			continue
+		}
+
+		if err := setValues(fv, raw); err != nil {
+			s.violation(field.Name, name, RuleInvalidType)
+		}
+	}
+}
+
+// taggedFields returns exported fields of the struct, including ones of embedded structs, by names in the tag.
+// Fields of forms fall back to json names.
+func taggedFields(rv reflect.Value, tag string) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+
+	for _, field := range reflect.VisibleFields(rv.Type()) {
+		if !field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" && tag == "form" {
+			name = jsonName(field)
+		}
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields[name] = field
+	}
+
+	return fields
+}
+
+func setValues(v reflect.Value, raw []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 && !v.Addr().Type().Implements(reflectx.TextUnmarshalerType) {
+		slice := reflect.MakeSlice(v.Type(), len(raw), len(raw))
+		for i, s := range raw {
+			if err := reflectx.SetValue(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+
+		return nil
+	}
+
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return reflectx.SetValue(v, raw[len(raw)-1])
+}
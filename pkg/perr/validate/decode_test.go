@@ -0,0 +1,114 @@
+package validate_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+type createOrderRequest struct {
+	Item  string        `json:"item" validate:"required"`
+	Count int           `json:"count" validate:"min:1"`
+	TTL   time.Duration `json:"ttl"`
+}
+
+type listOrdersRequest struct {
+	Limit int      `query:"limit"`
+	IDs   []int    `query:"id"`
+	Tags  []string `query:"tags"`
+}
+
+func TestDecodeQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		value func(t *testing.T) (any, error)
+		want  []string
+	}{
+		{
+			name: "json body ignores query",
+			value: func(t *testing.T) (any, error) {
+				return decode[createOrderRequest](t, "/orders?utm_source=mail", `{"item": "book", "count": 2}`)
+			},
+		},
+		{
+			name: "query fields",
+			value: func(t *testing.T) (any, error) {
+				return decode[listOrdersRequest](t, "/orders?limit=10&id=1&id=2&tags=a,b", "")
+			},
+		},
+		{
+			name: "unknown query parameter",
+			value: func(t *testing.T) (any, error) {
+				return decode[listOrdersRequest](t, "/orders?limit=10&limt=5", "")
+			},
+			want: []string{"limt"},
+		},
+		{
+			name: "invalid query parameter",
+			value: func(t *testing.T) (any, error) {
+				return decode[listOrdersRequest](t, "/orders?id=x", "")
+			},
+			want: []string{"id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.value(t)
+
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Decode() error = %v, want nil", err)
+				}
+
+				return
+			}
+
+			ve := validate.ToValidationError(err)
+			if ve == nil {
+				t.Fatalf("Decode() error = %v, want *validate.Error", err)
+			}
+
+			if len(ve.Violations) != len(tt.want) || ve.Violations[0].JSONPath != tt.want[0] {
+				t.Errorf("violations = %+v, want %v", ve.Violations, tt.want)
+			}
+		})
+	}
+
+	req, err := decode[listOrdersRequest](t, "/orders?limit=10&id=1&id=2&tags=a,b", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.Limit != 10 || len(req.IDs) != 2 || req.IDs[1] != 2 || len(req.Tags) != 1 || req.Tags[0] != "a,b" {
+		t.Errorf("Decode() = %+v, want limit 10, ids [1 2] and tags [a,b]", req)
+	}
+}
+
+func decode[T any](t *testing.T, url, body string) (T, error) {
+	t.Helper()
+
+	r := httptest.NewRequest("POST", url, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	return validate.Decode[T](r)
+}
+
+func TestDecodeBodyType(t *testing.T) {
+	_, err := decode[createOrderRequest](t, "/orders", `{"item": "book", "count": "two"}`)
+	if ve := validate.ToValidationError(err); ve == nil || len(ve.Violations) != 1 || ve.Violations[0].JSONPath != "count" {
+		t.Errorf("Decode() error = %v, want a violation of count", err)
+	}
+
+	_, err = decode[createOrderRequest](t, "/orders", `[1]`)
+	if validate.IsValidationError(err) || perr.CodeOf(err) != codes.InvalidArgument {
+		t.Errorf("Decode() error = %v, want a malformed body error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package validate
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/gookit/validate/locales/ruru"
+	"github.com/gookit/validate/locales/zhcn"
+	"github.com/gookit/validate/locales/zhtw"
+)
+
+// DefaultLocale is the locale of messages used when neither the context nor the validator set one.
+const DefaultLocale = "en"
+
+// enMessages are messages of rules added by the package, the rest of English messages are bundled with gookit.
+var enMessages = map[string]string{
+	"phone":        "{field} must be a phone number in international format",
+	"iban":         "{field} must be a valid IBAN",
+	"uuid_version": "{field} must be a valid UUID",
+	"required_if":  "{field} is required when {args0} is %[2]v",
+	"requiredIf":   "{field} is required when {args0} is %[2]v",
+
+	RuleUnknownField: "{field} is not a known field",
+	RuleInvalidType:  "{field} has a value of invalid type",
+}
+
+var ruMessages = map[string]string{
+	"phone":        "{field} должно быть номером телефона в международном формате",
+	"iban":         "{field} должно быть корректным IBAN",
+	"uuid_version": "{field} должно быть корректным UUID",
+	"required_if":  "{field} обязательно, когда {args0} равно %[2]v",
+	"requiredIf":   "{field} обязательно, когда {args0} равно %[2]v",
+
+	RuleUnknownField: "{field} не является известным полем",
+	RuleInvalidType:  "{field} имеет значение некорректного типа",
+}
+
+var (
+	cataloguesMu sync.RWMutex
+	catalogues   = map[string]map[string]string{
+		"en":    maps.Clone(enMessages),
+		"ru":    merged(ruru.Data, ruMessages),
+		"zh":    maps.Clone(zhcn.Data),
+		"zh-cn": maps.Clone(zhcn.Data),
+		"zh-tw": maps.Clone(zhtw.Data),
+	}
+)
+
+func merged(sets ...map[string]string) map[string]string {
+	out := make(map[string]string)
+	for _, set := range sets {
+		maps.Copy(out, set)
+	}
+
+	return out
+}
+
+/*
+RegisterMessages adds message templates for the locale, e.g. "de" or "pt-BR", to the catalogue shared by validators
+
+Keys are the same as of Validator.WithMessages. Bundled catalogues are "en" and "ru", messages of gookit are used for "zh-CN" and "zh-TW".
+*/
+func RegisterMessages(locale string, messages map[string]string) {
+	cataloguesMu.Lock()
+	defer cataloguesMu.Unlock()
+
+	// catalogues are replaced rather than updated, so validations reading them don't need the lock
+	locale = normalizeLocale(locale)
+	catalogues[locale] = merged(catalogues[locale], messages)
+}
+
+// catalogue returns messages of the locale, trying its base language if there is no exact one, e.g. "ru" for "ru-RU".
+func catalogue(locale string) (map[string]string, bool) {
+	cataloguesMu.RLock()
+	defer cataloguesMu.RUnlock()
+
+	if messages, ok := catalogues[locale]; ok {
+		return messages, true
+	}
+
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		messages, ok := catalogues[base]
+
+		return messages, ok
+	}
+
+	return nil, false
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+/*
+LocaleFromRequest returns the locale of messages for the request
+
+The locale stored in the context with perr.ContextWithLocale wins, otherwise the most preferred locale
+of the Accept-Language header having a catalogue is picked. DefaultLocale is returned if nothing matches.
+*/
+func LocaleFromRequest(r *http.Request) string {
+	if locale, ok := perr.LocaleFromContext(r.Context()); ok {
+		return locale
+	}
+
+	for _, locale := range parseAcceptLanguage(r.Header.Get("Accept-Language")) {
+		if _, ok := catalogue(normalizeLocale(locale)); ok {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+// LocaleMiddleware stores the locale of the request picked by LocaleFromRequest in its context.
+func LocaleMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := perr.ContextWithLocale(r.Context(), LocaleFromRequest(r))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// parseAcceptLanguage returns locales of the header like "ru-RU,ru;q=0.9,en;q=0.8" ordered by preference.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var locales []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed <= 0 {
+				continue
+			}
+
+			q = parsed
+		}
+
+		locales = append(locales, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].q > locales[j].q
+	})
+
+	out := make([]string, 0, len(locales))
+	for _, l := range locales {
+		out = append(out, l.locale)
+	}
+
+	return out
+}
+
+// localeOf returns the locale stored in ctx or the fallback.
+func localeOf(ctx context.Context, fallback string) string {
+	if locale, ok := perr.LocaleFromContext(ctx); ok {
+		return normalizeLocale(locale)
+	}
+
+	return fallback
+}
@@ -0,0 +1,158 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+/*
+Rule is a reusable validation rule, it is referenced in tags by its name like built-in ones
+
+	v := validate.New().WithRule(validate.Rule{
+		Name:    "slug",
+		Func:    func(value string) bool { return slugRe.MatchString(value) },
+		Message: "{field} must be a slug",
+	})
+
+Func is a func returning bool, the first argument is the value of the field and the rest are arguments of the rule
+from the tag, e.g. "prefix:ab" passes "ab". Message is a template of the failure message, see gookit/validate for the syntax,
+it is used unless the catalogue of the locale or the validator has a message for the rule.
+*/
+type Rule struct {
+	Name    string
+	Func    any
+	Message string
+
+	// values are allowed values of rules created by WithEnum, they are rendered to schemas
+	values []string
+}
+
+var (
+	phoneRe = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	ibanRe  = regexp.MustCompile(`^[A-Z]{2}\d{2}[A-Z0-9]{11,30}$`)
+	uuidRe  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-([1-8])[0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
+)
+
+/*
+builtinRules are registered on every validator, their messages are in the bundled catalogues:
+  - phone: a phone number in E.164 format, e.g. "+79991234567"
+  - iban: an IBAN with a valid checksum, spaces are allowed
+  - uuid_version: a UUID of any version, or of the given one with "uuid_version:4"
+
+Rules bundled with gookit are available too, e.g. "required_if:kind,company" for a field required if another one equals a value.
+*/
+var builtinRules = []Rule{
+	{Name: "phone", Func: isPhone},
+	{Name: "iban", Func: isIBAN},
+	{Name: "uuid_version", Func: isUUIDVersion},
+}
+
+func isPhone(value string) bool {
+	return phoneRe.MatchString(value)
+}
+
+func isIBAN(value string) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(value, " ", ""))
+	if !ibanRe.MatchString(iban) {
+		return false
+	}
+
+	// move the country code and the checksum to the end and replace letters with numbers, A = 10 ... Z = 35
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+
+	return ok && n.Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func isUUIDVersion(value string, version ...string) bool {
+	m := uuidRe.FindStringSubmatch(value)
+	if m == nil {
+		return false
+	}
+
+	return len(version) == 0 || m[1] == version[0]
+}
+
+// WithRule registers a rule, a rule with the same name is replaced.
+func (v *Validator) WithRule(rule Rule) *Validator {
+	v.rules = slices.DeleteFunc(v.rules, func(r Rule) bool {
+		return r.Name == rule.Name
+	})
+	v.rules = append(v.rules, rule)
+
+	return v
+}
+
+/*
+WithEnum registers a rule named name which accepts only the values, e.g. a set of currency codes
+
+	v := validate.New().WithEnum("currency", "USD", "EUR")
+
+	type Payment struct {
+		Currency string `json:"currency" validate:"required|currency"`
+	}
+*/
+func (v *Validator) WithEnum(name string, values ...string) *Validator {
+	allowed := slices.Clone(values)
+
+	return v.WithRule(Rule{
+		Name: name,
+		Func: func(value any) bool {
+			return slices.Contains(allowed, fmt.Sprint(value))
+		},
+		Message: "{field} must be one of: " + strings.Join(allowed, ", "),
+		values:  allowed,
+	})
+}
+
+/*
+Checker is implemented by structs with checks which can't be expressed with tags, e.g. uniqueness of an email in a repository
+
+Failures are reported as *Error, e.g. created with NewFieldError, they are merged with failures of the tags.
+Any other error, like a failed query, is returned by the validator as is.
+*/
+type Checker interface {
+	Validate(ctx context.Context) error
+}
+
+// check runs the hook of the struct if it implements Checker and merges its failures into e.
+func check(ctx context.Context, value any, e *Error) error {
+	checker, ok := value.(Checker)
+	if !ok || isNil(value) {
+		return nil
+	}
+
+	err := checker.Validate(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var ve *Error
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	e.merge(ve)
+
+	return nil
+}
+
+func isNil(value any) bool {
+	rv := reflect.ValueOf(value)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,147 @@
+package validate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// checkedScene is the name of the gookit scene fields selected by a scene or presence are validated in.
+const checkedScene = "platcom.checked"
+
+/*
+WithScenes registers scenes, sets of fields validated for an operation, by JSON paths of fields
+
+	v := validate.New().WithScenes(map[string][]string{
+		"create": {"name", "email", "password"},
+		"update": {"name", "email"},
+	})
+
+	err := v.ValidateScene(ctx, req, "update")
+
+Listing a nested struct selects all its fields. Structs may also define their own scenes with a method:
+
+	func (r *UserRequest) Scenes() map[string][]string
+*/
+func (v *Validator) WithScenes(scenes map[string][]string) *Validator {
+	for scene, fields := range scenes {
+		v.scenes[scene] = slices.Clone(fields)
+	}
+
+	return v
+}
+
+/*
+ValidateScene validates only fields of the scene registered with WithScenes or defined by the struct, see ValidateContext
+
+The scene is stored in the context passed to the Validate(ctx) hook, see SceneFromContext. Validating in an unknown scene
+is a programming error, it is reported as an error which isn't *Error.
+*/
+func (v *Validator) ValidateScene(ctx context.Context, value any, scene string) error {
+	jsonPaths, ok := v.scenes[scene]
+	if custom, isCustom := value.(interface{ Scenes() map[string][]string }); !ok && isCustom {
+		jsonPaths, ok = custom.Scenes()[scene]
+	}
+
+	if !ok {
+		return fmt.Errorf("validate: unknown scene %q", scene)
+	}
+
+	return v.validate(context.WithValue(ctx, sceneKey{}, scene), value, func(fields map[string]fieldInfo) []string {
+		paths := make([]string, 0, len(jsonPaths))
+		for _, jsonPath := range jsonPaths {
+			if info, ok := fields[jsonPath]; ok {
+				paths = append(paths, info.path)
+			}
+		}
+
+		return paths
+	})
+}
+
+/*
+ValidatePartial validates only fields present in the struct, e.g. in the body of a PATCH request, see ValidateContext
+
+Pointers, slices, maps and interfaces are present if they aren't nil, fields of other kinds if they aren't zero,
+so explicit zero values like 0 or false can't be told from missing ones and are skipped: make such fields pointers.
+Rules of present fields are checked as gookit checks them, and "required" passes for any non-nil pointer,
+so reject explicitly set empty values with rules like "min_len" instead:
+
+	type UpdateUserRequest struct {
+		Name  *string `json:"name" validate:"min_len:2"`
+		Email *string `json:"email" validate:"email"`
+		Age   *int    `json:"age" validate:"min:18"`
+	}
+*/
+func (v *Validator) ValidatePartial(ctx context.Context, value any) error {
+	return v.validate(ctx, value, func(map[string]fieldInfo) []string {
+		return presentFields(reflect.ValueOf(value), "")
+	})
+}
+
+// presentFields returns Go paths of present fields, nested structs are walked instead of being listed,
+// since listing a struct makes gookit check all its fields.
+func presentFields(rv reflect.Value, prefix string) []string {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct || rv.Type() == timeType {
+		return nil
+	}
+
+	var paths []string
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if !field.IsExported() || jsonName(field) == "-" {
+			continue
+		}
+
+		fv, path := rv.Field(i), prefix+field.Name
+
+		if isStruct(fv.Type()) {
+			paths = append(paths, presentFields(fv, path+".")...)
+
+			continue
+		}
+
+		if !fv.IsZero() {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+func isStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+type sceneKey struct{}
+
+// SceneFromContext returns the scene the struct is validated in by ValidateScene, for use in Validate(ctx) hooks.
+func SceneFromContext(ctx context.Context) (string, bool) {
+	scene, ok := ctx.Value(sceneKey{}).(string)
+
+	return scene, ok
+}
+
+// ValidateScene validates only fields of the scene with the default validator, see Validator.ValidateScene.
+func ValidateScene(ctx context.Context, v any, scene string) error {
+	return defaultValidator.ValidateScene(ctx, v, scene)
+}
+
+// ValidatePartial validates only present fields with the default validator, see Validator.ValidatePartial.
+func ValidatePartial(ctx context.Context, v any) error {
+	return defaultValidator.ValidatePartial(ctx, v)
+}
@@ -0,0 +1,141 @@
+package validate_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+type sceneRequest struct {
+	Name     string `json:"name" validate:"required|min_len:2"`
+	Email    string `json:"email" validate:"required|email"`
+	Password string `json:"password" validate:"required|min_len:8"`
+
+	scene string
+}
+
+func (r *sceneRequest) Scenes() map[string][]string {
+	return map[string][]string{"rename": {"name"}}
+}
+
+func (r *sceneRequest) Validate(ctx context.Context) error {
+	r.scene, _ = validate.SceneFromContext(ctx)
+
+	return nil
+}
+
+type patchRequest struct {
+	Name    *string      `json:"name" validate:"required|min_len:2"`
+	Age     *int         `json:"age" validate:"min:18"`
+	Limit   int          `json:"limit" validate:"min:10"`
+	Address patchAddress `json:"address"`
+}
+
+type patchAddress struct {
+	City *string `json:"city" validate:"min_len:2"`
+}
+
+// violated returns sorted JSON paths of violations of err, or an error if it isn't *validate.Error.
+func violated(err error) ([]string, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	ve := validate.ToValidationError(err)
+	if ve == nil {
+		return nil, fmt.Errorf("%v isn't *validate.Error", err)
+	}
+
+	paths := make([]string, 0, len(ve.Violations))
+	for _, v := range ve.Violations {
+		paths = append(paths, v.JSONPath)
+	}
+
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+func TestValidateScene(t *testing.T) {
+	v := validate.New().WithScenes(map[string][]string{
+		"update": {"name", "email"},
+	})
+
+	tests := []struct {
+		name  string
+		scene string
+		want  []string
+	}{
+		{name: "registered", scene: "update", want: []string{"email", "name"}},
+		{name: "defined by the struct", scene: "rename", want: []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &sceneRequest{Name: "b", Email: "bob"}
+
+			got, err := violated(v.ValidateScene(context.Background(), req, tt.scene))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("passed to the hook", func(t *testing.T) {
+		req := &sceneRequest{Name: "bob", Email: "bob@example.com"}
+
+		if err := v.ValidateScene(context.Background(), req, "update"); err != nil {
+			t.Fatalf("ValidateScene() = %v, want nil", err)
+		}
+
+		if req.scene != "update" {
+			t.Errorf("SceneFromContext() = %q, want update", req.scene)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		err := v.ValidateScene(context.Background(), &sceneRequest{}, "delete")
+		if err == nil || validate.IsValidationError(err) {
+			t.Errorf("ValidateScene() = %v, want an error which isn't *validate.Error", err)
+		}
+	})
+}
+
+func TestValidatePartial(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	age := func(n int) *int { return &n }
+
+	tests := []struct {
+		name  string
+		value *patchRequest
+		want  []string
+	}{
+		{name: "empty", value: &patchRequest{}},
+		{name: "valid", value: &patchRequest{Name: ptr("bob"), Age: age(20), Limit: 10}},
+		{name: "invalid", value: &patchRequest{Name: ptr("b"), Age: age(3), Limit: 5}, want: []string{"age", "limit", "name"}},
+		{name: "explicit zero pointer", value: &patchRequest{Age: age(0)}, want: []string{"age"}},
+		// "required" passes for non-nil pointers, empty values fail on other rules
+		{name: "explicit empty string", value: &patchRequest{Name: ptr("")}, want: []string{"name"}},
+		{name: "nested", value: &patchRequest{Address: patchAddress{City: ptr("P")}}, want: []string{"address.city"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := violated(validate.ValidatePartial(context.Background(), tt.value))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
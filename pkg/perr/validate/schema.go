@@ -0,0 +1,347 @@
+package validate
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/defany/platcom/v2/pkg/internal/reflectx"
+)
+
+/*
+Schema is a JSON Schema of a type, limited to keywords shared with OpenAPI and Swagger 2.0 definitions
+
+Nested structs are inlined, so a schema can be put into definitions or components as is. Recursive types can't be inlined,
+they are referenced with $ref instead, see Validator.Definitions.
+*/
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// DefinitionsRef is the prefix of references to definitions of Swagger 2.0 files,
+// OpenAPI 3 files keep them under "#/components/schemas/".
+const DefinitionsRef = "#/definitions/"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaOf generates a schema of the type of v with the default validator, see Validator.Schema.
+func SchemaOf(v any) *Schema {
+	return defaultValidator.Schema(v)
+}
+
+/*
+Schema generates a schema of the type of value from json tags and rules in the tag of the validator
+
+Rules with a counterpart in JSON Schema are translated: required, min and max, lengths, between, enum and in, regex,
+email, url, uuid, phone, and enums registered with WithEnum. Other rules, like custom ones, are only checked at runtime.
+
+Recursive types are referenced with $ref to DefinitionsRef and the qualified name of the type, see Definitions.
+*/
+func (v *Validator) Schema(value any) *Schema {
+	name := typeName(reflect.TypeOf(value))
+
+	return v.Definitions(name, value, DefinitionsRef)[name]
+}
+
+// DefinitionsOf generates definitions of the type of v with the default validator, see Validator.Definitions.
+func DefinitionsOf(name string, v any, refPrefix string) map[string]*Schema {
+	return defaultValidator.Definitions(name, v, refPrefix)
+}
+
+/*
+Definitions generates a schema of the type of value under the name, see Schema, and schemas of recursive types it references
+
+Recursive types are referenced with $ref to refPrefix and the name, the given one for the type of value itself
+and the name of the type qualified with its package path for nested ones, so all definitions have to be added to the file:
+
+	for name, schema := range validate.DefinitionsOf("Node", Node{}, validate.DefinitionsRef) {
+		definitions[name] = schema
+	}
+*/
+func (v *Validator) Definitions(name string, value any, refPrefix string) map[string]*Schema {
+	t := reflect.TypeOf(value)
+
+	g := &schemaGen{
+		v:         v,
+		refPrefix: refPrefix,
+		names:     map[reflect.Type]string{derefType(t): name},
+		visiting:  make(map[reflect.Type]bool),
+		defs:      make(map[string]*Schema),
+	}
+
+	if s := g.schemaOf(t); g.defs[name] == nil {
+		g.defs[name] = s
+	}
+
+	return g.defs
+}
+
+// schemaGen generates a schema of a type, structs being generated are visited, so a type met again is recursive.
+type schemaGen struct {
+	v         *Validator
+	refPrefix string
+	// names are names of definitions of recursive types
+	names    map[reflect.Type]string
+	visiting map[reflect.Type]bool
+	defs     map[string]*Schema
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+/*
+typeName returns the name of definitions of the type qualified with its package path, e.g. "github.com.acme.api.Node",
+so types with the same name from different packages don't collide
+
+Slashes are replaced with dots, since they separate tokens of $ref pointers.
+*/
+func typeName(t reflect.Type) string {
+	t = derefType(t)
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+
+	return strings.ReplaceAll(t.PkgPath()+"."+t.Name(), "/", ".")
+}
+
+func (g *schemaGen) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	if t == reflectx.DurationType {
+		return &Schema{Type: "string", Format: "duration"}
+	}
+
+	if reflect.PointerTo(t).Implements(reflectx.TextUnmarshalerType) || t.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.structSchema(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) *Schema {
+	if g.visiting[t] {
+		name, ok := g.names[t]
+		if !ok {
+			name = typeName(t)
+			g.names[t] = name
+		}
+
+		return &Schema{Ref: g.refPrefix + name}
+	}
+
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || isEmbedded(field) || nestedInNamed(t, field.Index) {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		property := g.schemaOf(field.Type)
+
+		rules := parseRules(field.Tag.Get(g.v.tag))
+		for rule, params := range rules {
+			if normalizeRule(rule) == "required" {
+				s.Required = append(s.Required, name)
+
+				continue
+			}
+
+			g.v.applyRule(property, rule, params)
+		}
+
+		s.Properties[name] = property
+	}
+
+	if name, ok := g.names[t]; ok {
+		g.defs[name] = s
+	}
+
+	return s
+}
+
+// isEmbedded reports whether encoding/json merges fields of the field into the parent object:
+// embedded structs with a name in the json tag are properties of their own.
+func isEmbedded(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	return field.Anonymous && name == "" && derefType(field.Type).Kind() == reflect.Struct
+}
+
+// nestedInNamed reports whether the field by index is promoted from an embedded struct which isn't merged, see isEmbedded.
+func nestedInNamed(t reflect.Type, index []int) bool {
+	for i := 1; i < len(index); i++ {
+		if embedded := t.FieldByIndex(index[:i]); !isEmbedded(embedded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyRule translates the rule to keywords of the schema, rules without a counterpart are skipped.
+func (v *Validator) applyRule(s *Schema, rule string, params []string) {
+	switch normalizeRule(rule) {
+	case "min":
+		s.Minimum = parseFloat(params, 0)
+	case "max":
+		s.Maximum = parseFloat(params, 0)
+	case "between":
+		s.Minimum, s.Maximum = parseFloat(params, 0), parseFloat(params, 1)
+	case "minlen", "minlength":
+		s.setMinLength(parseInt(params, 0))
+	case "maxlen", "maxlength":
+		s.setMaxLength(parseInt(params, 0))
+	case "len", "length":
+		s.setMinLength(parseInt(params, 0))
+		s.setMaxLength(parseInt(params, 0))
+	case "enum", "in":
+		s.Enum = enumOf(s.Type, params)
+	case "regex", "regexp":
+		// gookit doesn't split arguments of regex rules
+		s.Pattern = strings.Join(params, ",")
+	case "email", "isemail":
+		s.Format = "email"
+	case "url", "isurl", "fullurl", "isfullurl":
+		s.Format = "uri"
+	case "uuid", "isuuid", "uuid3", "isuuid3", "uuid4", "isuuid4", "uuid5", "isuuid5", "uuidversion":
+		s.Format = "uuid"
+	case "phone":
+		s.Pattern = phoneRe.String()
+	case "iban":
+		s.Pattern = ibanRe.String()
+	default:
+		for _, r := range v.rules {
+			if r.Name == rule && r.values != nil {
+				s.Enum = enumOf(s.Type, r.values)
+			}
+		}
+	}
+}
+
+func (s *Schema) setMinLength(n *int) {
+	if s.Type == "array" {
+		s.MinItems = n
+	} else {
+		s.MinLength = n
+	}
+}
+
+func (s *Schema) setMaxLength(n *int) {
+	if s.Type == "array" {
+		s.MaxItems = n
+	} else {
+		s.MaxLength = n
+	}
+}
+
+// enumOf converts values of the rule to the type of the schema, so integer enums aren't rendered as strings.
+func enumOf(typ string, values []string) []any {
+	enum := make([]any, 0, len(values))
+
+	for _, value := range values {
+		switch typ {
+		case "integer":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				enum = append(enum, n)
+
+				continue
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				enum = append(enum, f)
+
+				continue
+			}
+		}
+
+		enum = append(enum, value)
+	}
+
+	return enum
+}
+
+func parseFloat(params []string, i int) *float64 {
+	if i >= len(params) {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(params[i]), 64)
+	if err != nil {
+		return nil
+	}
+
+	return &f
+}
+
+func parseInt(params []string, i int) *int {
+	if i >= len(params) {
+		return nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(params[i]))
+	if err != nil {
+		return nil
+	}
+
+	return &n
+}
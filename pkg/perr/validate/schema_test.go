@@ -0,0 +1,102 @@
+package validate_test
+
+import (
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+type node struct {
+	Name     string `json:"name" validate:"required"`
+	Children []node `json:"children"`
+	Leaf     *leaf  `json:"leaf"`
+}
+
+type leaf struct {
+	Value  int   `json:"value"`
+	Parent *node `json:"parent"`
+	Next   *leaf `json:"next"`
+}
+
+// leafName is the name of the definition of leaf, qualified with the package path.
+const leafName = "github.com.defany.platcom.v2.pkg.perr.validate_test.leaf"
+
+func TestSchemaRecursive(t *testing.T) {
+	defs := validate.DefinitionsOf("Node", node{}, validate.DefinitionsRef)
+
+	root, ok := defs["Node"]
+	if !ok {
+		t.Fatalf("definitions = %v, want Node", defs)
+	}
+
+	if got := root.Properties["children"].Items.Ref; got != "#/definitions/Node" {
+		t.Errorf("children items $ref = %q, want #/definitions/Node", got)
+	}
+
+	if got := root.Properties["leaf"].Properties["parent"].Ref; got != "#/definitions/Node" {
+		t.Errorf("leaf.parent $ref = %q, want #/definitions/Node", got)
+	}
+
+	if got := root.Properties["leaf"].Properties["next"].Ref; got != "#/definitions/"+leafName {
+		t.Errorf("leaf.next $ref = %q, want #/definitions/%s", got, leafName)
+	}
+
+	def, ok := defs[leafName]
+	if !ok {
+		t.Fatalf("definitions = %v, want leaf", defs)
+	}
+
+	if def.Properties["value"].Type != "integer" {
+		t.Errorf("leaf.value type = %q, want integer", def.Properties["value"].Type)
+	}
+
+	if len(root.Required) != 1 || root.Required[0] != "name" {
+		t.Errorf("required = %v, want [name]", root.Required)
+	}
+}
+
+func TestSchemaOfRecursive(t *testing.T) {
+	s := validate.SchemaOf(&node{})
+
+	want := "#/definitions/github.com.defany.platcom.v2.pkg.perr.validate_test.node"
+	if got := s.Properties["children"].Items.Ref; got != want {
+		t.Errorf("children items $ref = %q, want %s", got, want)
+	}
+}
+
+type Audit struct {
+	CreatedBy string `json:"created_by" validate:"required"`
+}
+
+type Paging struct {
+	Limit int `json:"limit" validate:"max:100"`
+}
+
+type listRequest struct {
+	Audit
+	*Paging `json:"paging"`
+
+	Query string `json:"query"`
+}
+
+func TestSchemaEmbedded(t *testing.T) {
+	s := validate.SchemaOf(listRequest{})
+
+	for _, name := range []string{"created_by", "paging", "query"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("properties = %v, want %s", s.Properties, name)
+		}
+	}
+
+	if _, ok := s.Properties["limit"]; ok {
+		t.Error("properties have limit, want it nested in paging like encoding/json does")
+	}
+
+	if limit := s.Properties["paging"].Properties["limit"]; limit == nil || limit.Maximum == nil || *limit.Maximum != 100 {
+		t.Errorf("paging.limit = %+v, want maximum 100", limit)
+	}
+
+	if len(s.Required) != 1 || s.Required[0] != "created_by" {
+		t.Errorf("required = %v, want [created_by]", s.Required)
+	}
+}
@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+/*
+Status converts the error to a google.rpc.Status with a google.rpc.BadRequest detail
+
+Field violations become violations of the detail by their JSON paths. Details without violations, like ones of NewError,
+become violations without a field. The code is the same number in both, since codes match gRPC ones.
+*/
+func (e ErrorWithDetails) Status() (*spb.Status, error) {
+	st := &spb.Status{
+		Code:    int32(e.Code),
+		Message: e.Message,
+	}
+
+	badRequest := e.badRequest()
+	if len(badRequest.FieldViolations) == 0 {
+		return st, nil
+	}
+
+	detail, err := anypb.New(badRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Details = append(st.Details, detail)
+
+	return st, nil
+}
+
+func (e ErrorWithDetails) badRequest() *errdetails.BadRequest {
+	badRequest := &errdetails.BadRequest{}
+
+	for _, v := range e.FieldViolations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.JSONPath,
+			Description: v.Message,
+		})
+	}
+
+	if len(e.FieldViolations) > 0 {
+		return badRequest
+	}
+
+	for _, detail := range e.Details {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Description: detail,
+		})
+	}
+
+	return badRequest
+}
+
+// MarshalStatus encodes the error as a google.rpc.Status in the binary protobuf format.
+func (e ErrorWithDetails) MarshalStatus() ([]byte, error) {
+	st, err := e.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(st)
+}
+
+/*
+MarshalStatusJSON encodes the error as a google.rpc.Status in the canonical JSON form, the one gRPC-gateway responds with
+
+	{
+		"code": 3,
+		"message": "bad validation",
+		"details": [{
+			"@type": "type.googleapis.com/google.rpc.BadRequest",
+			"fieldViolations": [{"field": "address.city", "description": "address.city min length is 2"}]
+		}]
+	}
+*/
+func (e ErrorWithDetails) MarshalStatusJSON() ([]byte, error) {
+	st, err := e.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	return protojson.Marshal(st)
+}
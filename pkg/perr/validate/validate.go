@@ -2,20 +2,21 @@ package validate
 
 import (
 	"encoding/json"
-	"errors"
 
+	"github.com/defany/platcom/v2/pkg/perr"
 	"github.com/defany/platcom/v2/pkg/perr/codes"
-	"github.com/gookit/validate"
 )
 
 type ErrorWithDetails struct {
-	Code    codes.Code `json:"code"`
-	Message string     `json:"message"`
-	Details []string   `json:"details,omitempty"`
+	Code            codes.Code       `json:"code"`
+	Message         string           `json:"message"`
+	Details         []string         `json:"details,omitempty"`
+	FieldViolations []FieldViolation `json:"field_violations,omitempty"`
 }
 
 type Error struct {
-	Messages []string `json:"error_messages"`
+	Messages   []string         `json:"error_messages"`
+	Violations []FieldViolation `json:"field_violations,omitempty"`
 }
 
 func NewError(messages ...string) *Error {
@@ -24,22 +25,36 @@ func NewError(messages ...string) *Error {
 	}
 }
 
-func NewValidate(v any) error {
-	validate.Config(func(opt *validate.GlobalOption) {
-		opt.StopOnError = false
-	})
+// NewFieldError creates an error from violations, their messages become Messages of the error.
+func NewFieldError(violations ...FieldViolation) *Error {
+	e := &Error{}
 
-	if r := validate.Struct(v); !r.Validate() {
-		return NewError(r.Errors.String())
+	for _, violation := range violations {
+		e.addViolation(violation)
 	}
 
-	return nil
+	return e
+}
+
+// NewValidate validates the struct with the default validator, see Validator.Validate.
+func NewValidate(v any) error {
+	return defaultValidator.Validate(v)
 }
 
 func (e *Error) addError(message string) {
 	e.Messages = append(e.Messages, message)
 }
 
+func (e *Error) addViolation(violation FieldViolation) {
+	e.Violations = append(e.Violations, violation)
+	e.addError(violation.Message)
+}
+
+func (e *Error) merge(other *Error) {
+	e.Messages = append(e.Messages, other.Messages...)
+	e.Violations = append(e.Violations, other.Violations...)
+}
+
 func (e *Error) Error() string {
 	v, err := json.Marshal(e.Messages)
 	if err != nil {
@@ -51,22 +66,21 @@ func (e *Error) Error() string {
 
 func (e *Error) ErrorWithDetails() ErrorWithDetails {
 	return ErrorWithDetails{
-		Code:    codes.InvalidArgument,
-		Message: "bad validation",
-		Details: e.Messages,
+		Code:            codes.InvalidArgument,
+		Message:         "bad validation",
+		Details:         e.Messages,
+		FieldViolations: e.Violations,
 	}
 }
 
 func IsValidationError(err error) bool {
-	var ve *Error
-	return errors.As(err, &ve)
+	_, ok := perr.As[*Error](err)
+	return ok
 }
 
+// ToValidationError returns the first *Error in the chain of err, or nil if there is none.
 func ToValidationError(err error) *Error {
-	var ce *Error
-	if !IsValidationError(err) {
-		return nil
-	}
+	ve, _ := perr.As[*Error](err)
 
-	return ce
+	return ve
 }
@@ -0,0 +1,127 @@
+package validate_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/defany/platcom/v2/pkg/perr"
+	"github.com/defany/platcom/v2/pkg/perr/codes"
+	"github.com/defany/platcom/v2/pkg/perr/validate"
+)
+
+func TestToValidationError(t *testing.T) {
+	ve := validate.NewError("name is required")
+
+	tests := []struct {
+		name string
+		err  error
+		want *validate.Error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "plain error", err: errors.New("boom"), want: nil},
+		{name: "perr error", err: perr.New("not found", codes.NotFound), want: nil},
+		{name: "validation error", err: ve, want: ve},
+		{name: "wrapped with fmt", err: fmt.Errorf("create user: %w", ve), want: ve},
+		{name: "wrapped with perr", err: perr.Wrap(ve, codes.InvalidArgument, "bad request"), want: ve},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validate.ToValidationError(tt.err); got != tt.want {
+				t.Errorf("ToValidationError() = %v, want %v", got, tt.want)
+			}
+
+			if got := validate.IsValidationError(tt.err); got != (tt.want != nil) {
+				t.Errorf("IsValidationError() = %v, want %v", got, tt.want != nil)
+			}
+		})
+	}
+}
+
+func TestNewValidate(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required|min_len:2"`
+	}
+
+	type user struct {
+		Name    string  `json:"name" validate:"required"`
+		Age     int     `json:"age" validate:"min:18"`
+		Address address `json:"address"`
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		want  []validate.FieldViolation
+	}{
+		{
+			name:  "valid",
+			value: &user{Name: "bob", Age: 20, Address: address{City: "Paris"}},
+		},
+		{
+			name:  "invalid",
+			value: &user{Age: 3, Address: address{City: "P"}},
+			want: []validate.FieldViolation{
+				{Field: "Address.City", JSONPath: "address.city", Rule: "min_len", Params: []string{"2"}},
+				{Field: "Age", JSONPath: "age", Rule: "min", Params: []string{"18"}},
+				{Field: "Name", JSONPath: "name", Rule: "required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.NewValidate(tt.value)
+
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("NewValidate() = %v, want nil", err)
+				}
+
+				return
+			}
+
+			ve := validate.ToValidationError(err)
+			if ve == nil {
+				t.Fatalf("NewValidate() = %v, want *validate.Error", err)
+			}
+
+			if len(ve.Violations) != len(tt.want) {
+				t.Fatalf("NewValidate() violations = %+v, want %+v", ve.Violations, tt.want)
+			}
+
+			for i, got := range ve.Violations {
+				want := tt.want[i]
+				if got.Field != want.Field || got.JSONPath != want.JSONPath || got.Rule != want.Rule || fmt.Sprint(got.Params) != fmt.Sprint(want.Params) {
+					t.Errorf("violation %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewValidateRecursive(t *testing.T) {
+	err := validate.NewValidate(&node{Leaf: &leaf{Next: &leaf{}}})
+
+	ve := validate.ToValidationError(err)
+	if ve == nil {
+		t.Fatalf("NewValidate() = %v, want *validate.Error", err)
+	}
+
+	want := map[string]string{
+		"leaf.next.parent.name": "Leaf.Next.Parent.Name",
+		"leaf.parent.name":      "Leaf.Parent.Name",
+		"name":                  "Name",
+	}
+
+	if len(ve.Violations) != len(want) {
+		t.Fatalf("violations = %+v, want %d", ve.Violations, len(want))
+	}
+
+	for _, v := range ve.Violations {
+		if want[v.JSONPath] != v.Field {
+			t.Errorf("field of %s = %q, want %q", v.JSONPath, v.Field, want[v.JSONPath])
+		}
+	}
+}
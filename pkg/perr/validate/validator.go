@@ -0,0 +1,201 @@
+package validate
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gookit/validate"
+)
+
+/*
+Validator validates structs with gookit/validate without touching its global options
+
+Configure it once with the With* methods before sharing, after that it is safe for concurrent use:
+
+	v := validate.New().WithStopOnError(true).WithLocale("ru")
+
+	if err := v.Validate(req); err != nil {
+		return err
+	}
+*/
+type Validator struct {
+	stopOnError bool
+	tag         string
+	locale      string
+	messages    map[string]string
+	localized   map[string]map[string]string
+	rules       []Rule
+	scenes      map[string][]string
+}
+
+// New creates a validator reporting every failed rule, reading rules from the "validate" tag, with built-in rules registered.
+func New() *Validator {
+	return &Validator{
+		tag:       validate.Option().ValidateTag,
+		locale:    DefaultLocale,
+		messages:  make(map[string]string),
+		localized: make(map[string]map[string]string),
+		rules:     slices.Clone(builtinRules),
+		scenes:    make(map[string][]string),
+	}
+}
+
+// WithStopOnError makes the validator stop on the first failed rule.
+func (v *Validator) WithStopOnError(stop bool) *Validator {
+	v.stopOnError = stop
+
+	return v
+}
+
+// WithTag sets the name of the struct tag rules are read from.
+func (v *Validator) WithTag(tag string) *Validator {
+	v.tag = tag
+
+	return v
+}
+
+// WithLocale sets the locale of messages used if the context has none, DefaultLocale by default.
+func (v *Validator) WithLocale(locale string) *Validator {
+	v.locale = normalizeLocale(locale)
+
+	return v
+}
+
+/*
+WithMessages adds message templates overriding the catalogues for every locale
+
+Keys are rule names like "required" or a JSON path of a field and a rule like "address.city.required",
+see gookit/validate for the syntax of templates.
+*/
+func (v *Validator) WithMessages(messages map[string]string) *Validator {
+	maps.Copy(v.messages, messages)
+
+	return v
+}
+
+// WithLocaleMessages adds message templates for the locale overriding the catalogue and WithMessages, keys are the same.
+func (v *Validator) WithLocaleMessages(locale string, messages map[string]string) *Validator {
+	locale = normalizeLocale(locale)
+
+	if v.localized[locale] == nil {
+		v.localized[locale] = make(map[string]string, len(messages))
+	}
+
+	maps.Copy(v.localized[locale], messages)
+
+	return v
+}
+
+// Validate validates the struct with context.Background(), see ValidateContext.
+func (v *Validator) Validate(value any) error {
+	return v.ValidateContext(context.Background(), value)
+}
+
+/*
+ValidateContext validates the struct by its tags and then by its Validate(ctx) method if it implements Checker
+
+Failures of both are returned as a single *Error with field violations.
+Messages are in the locale stored in ctx with perr.ContextWithLocale, or in the locale of the validator.
+The hook is skipped if the tags failed and the validator stops on error.
+*/
+func (v *Validator) ValidateContext(ctx context.Context, value any) error {
+	return v.validate(ctx, value, nil)
+}
+
+// validate validates the struct, only the fields selected by only, if it isn't nil, are validated by tags.
+func (v *Validator) validate(ctx context.Context, value any, only func(fields map[string]fieldInfo) []string) error {
+	fields := fieldsOf(value, v.tag)
+	e := &Error{}
+
+	var checked []string
+	if only != nil {
+		checked = only(fields)
+	}
+
+	if only == nil || len(checked) > 0 {
+		if r := v.validation(value, fields, localeOf(ctx, v.locale), checked); !r.Validate() {
+			e = NewFieldError(violationsOf(fields, r.Errors)...)
+		}
+	}
+
+	if !v.stopOnError || len(e.Messages) == 0 {
+		if err := check(ctx, value, e); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Messages) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+func (v *Validator) validation(value any, fields map[string]fieldInfo, locale string, checked []string) *validate.Validation {
+	data, err := validate.FromStruct(value)
+	data.ValidateTag = v.tag
+
+	r := data.Create(err)
+	r.StopOnError = v.stopOnError
+
+	// fields are selected with a scene of gookit, which also checks nested fields of the listed ones
+	if len(checked) > 0 {
+		r.WithScenes(map[string][]string{checkedScene: checked}).AtScene(checkedScene)
+	}
+
+	for _, rule := range v.rules {
+		r.AddValidator(rule.Name, rule.Func)
+
+		if rule.Message != "" {
+			r.AddMessages(map[string]string{rule.Name: rule.Message})
+		}
+	}
+
+	// English is the fallback for messages missing in the catalogue of the locale
+	if messages, ok := catalogue(DefaultLocale); ok {
+		r.AddMessages(messages)
+	}
+
+	if messages, ok := catalogue(locale); ok && locale != DefaultLocale {
+		r.AddMessages(messages)
+	}
+
+	r.AddMessages(messagesOf(v.messages, fields))
+
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		r.AddMessages(messagesOf(v.localized[base], fields))
+	}
+
+	r.AddMessages(messagesOf(v.localized[locale], fields))
+
+	// messages of the struct itself are the most specific, so they win over the validator ones
+	if custom, ok := value.(interface{ Messages() map[string]string }); ok {
+		r.AddMessages(custom.Messages())
+	}
+
+	return r
+}
+
+var defaultValidator = New()
+
+// messagesOf rewrites keys of field messages from JSON paths to Go field paths gookit looks messages up by.
+func messagesOf(messages map[string]string, fields map[string]fieldInfo) map[string]string {
+	out := make(map[string]string, len(messages))
+
+	for key, message := range messages {
+		out[key] = message
+
+		i := strings.LastIndex(key, ".")
+		if i < 0 {
+			continue
+		}
+
+		if info, ok := fields[key[:i]]; ok {
+			out[info.path+key[i:]] = message
+		}
+	}
+
+	return out
+}
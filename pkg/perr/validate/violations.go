@@ -0,0 +1,175 @@
+package validate
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gookit/validate"
+)
+
+// FieldViolation describes a single failed rule of a field.
+type FieldViolation struct {
+	// Field is a path built from Go field names, e.g. "Address.City".
+	Field string `json:"field"`
+	// JSONPath is a dotted path built from JSON names, e.g. "address.city".
+	JSONPath string `json:"json_path"`
+	// Rule is a name of the failed rule, e.g. "min_len".
+	Rule string `json:"rule"`
+	// Message is a human-readable description of the failure.
+	Message string `json:"message"`
+	// Params are arguments of the rule, e.g. ["3"] for "min_len:3".
+	Params []string `json:"params,omitempty"`
+}
+
+// fieldInfo is what violations need to know about a field of the validated struct.
+type fieldInfo struct {
+	path  string
+	rules map[string][]string
+}
+
+// fieldsOf returns fields of the struct v by JSON path.
+func fieldsOf(v any, tagName string) map[string]fieldInfo {
+	fields := make(map[string]fieldInfo)
+	collectFields(reflect.ValueOf(v), reflect.TypeOf(v), "", "", tagName, fields, make(map[reflect.Type]bool))
+
+	return fields
+}
+
+// violationsOf converts gookit errors keyed by JSON paths to violations sorted by JSON path and rule.
+func violationsOf(fields map[string]fieldInfo, errs validate.Errors) []FieldViolation {
+	violations := make([]FieldViolation, 0, len(errs))
+
+	for jsonPath, messages := range errs {
+		info, ok := fields[jsonPath]
+		if !ok {
+			info = fieldInfo{path: jsonPath}
+		}
+
+		for rule, message := range messages {
+			violations = append(violations, FieldViolation{
+				Field:    info.path,
+				JSONPath: jsonPath,
+				Rule:     rule,
+				Message:  message,
+				Params:   ruleParams(info.rules, rule),
+			})
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].JSONPath != violations[j].JSONPath {
+			return violations[i].JSONPath < violations[j].JSONPath
+		}
+
+		return violations[i].Rule < violations[j].Rule
+	})
+
+	return violations
+}
+
+/*
+collectFields walks fields of the struct rv of type t, like gookit does, fields behind nil pointers are walked by their types
+
+Types walked without a value are visited, so fields of a recursive type behind a nil pointer are walked once.
+*/
+func collectFields(rv reflect.Value, t reflect.Type, goPrefix, jsonPrefix, tagName string, fields map[string]fieldInfo, visiting map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+
+		if rv.IsValid() && rv.IsNil() {
+			rv = reflect.Value{}
+		} else if rv.IsValid() {
+			rv = rv.Elem()
+		}
+	}
+
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	if !rv.IsValid() {
+		if visiting[t] {
+			return
+		}
+
+		visiting[t] = true
+		defer delete(visiting, t)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		goPath, jsonPath := goPrefix+field.Name, jsonPrefix+name
+
+		fields[jsonPath] = fieldInfo{
+			path:  goPath,
+			rules: parseRules(field.Tag.Get(tagName)),
+		}
+
+		var fv reflect.Value
+		if rv.IsValid() {
+			fv = rv.Field(i)
+		}
+
+		collectFields(fv, field.Type, goPath+".", jsonPath+".", tagName, fields, visiting)
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// parseRules parses a gookit rule tag like "required|min_len:3|enum:a,b" to params by rule name.
+func parseRules(tag string) map[string][]string {
+	rules := make(map[string][]string)
+
+	for _, rule := range strings.Split(tag, "|") {
+		name, args, _ := strings.Cut(strings.TrimSpace(rule), ":")
+		if name == "" {
+			continue
+		}
+
+		var params []string
+		if args != "" {
+			params = strings.Split(args, ",")
+		}
+
+		rules[name] = params
+	}
+
+	return rules
+}
+
+// ruleParams finds params of the rule, gookit reports rules both as "min_len" and "minLen".
+func ruleParams(rules map[string][]string, rule string) []string {
+	if params, ok := rules[rule]; ok {
+		return params
+	}
+
+	normalized := normalizeRule(rule)
+	for name, params := range rules {
+		if normalizeRule(name) == normalized {
+			return params
+		}
+	}
+
+	return nil
+}
+
+func normalizeRule(rule string) string {
+	return strings.ToLower(strings.ReplaceAll(rule, "_", ""))
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/defany/platcom/v2/pkg/perr/validate"
 	"github.com/rakyll/statik/fs"
 )
 
@@ -16,13 +17,17 @@ type Serve struct {
 
 	host *string
 
+	// schemas are values schemas are generated for by name, see WithSchema
+	schemas map[string]any
+
 	content []byte
 }
 
 func NewServe(path string) *Serve {
 	s := &Serve{
-		log:  slog.Default(),
-		path: path,
+		log:     slog.Default(),
+		path:    path,
+		schemas: make(map[string]any),
 	}
 
 	return s
@@ -40,12 +45,34 @@ func (s *Serve) WithHost(host string) *Serve {
 	return s
 }
 
+/*
+WithSchema adds a schema of the type of v generated from its validate tags, see validate.SchemaOf
+
+The schema replaces the one with the same name in definitions of Swagger 2.0 files or in components.schemas of OpenAPI 3 files,
+so the docs match runtime validation. Schemas of recursive types referenced by it are added under qualified names of the types.
+*/
+func (s *Serve) WithSchema(name string, v any) *Serve {
+	s.schemas[name] = v
+
+	return s
+}
+
+/*
+Middleware serves the swagger file prepared by Setup
+
+Deprecated: path is ignored, the served file is the one NewServe got. Use Handler.
+*/
 func (s *Serve) Middleware(path string) http.HandlerFunc {
+	return s.Handler()
+}
+
+// Handler serves the swagger file prepared by Setup.
+func (s *Serve) Handler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
 		w.WriteHeader(http.StatusOK)
-		_, err := w.Write(s.content[path])
+		_, err := w.Write(s.content)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -94,6 +121,12 @@ func (s *Serve) Setup() error {
 		schema["host"] = *s.host
 	}
 
+	if len(s.schemas) > 0 {
+		log.Info("adding schemas to swagger file", slog.Int("count", len(s.schemas)))
+
+		s.addSchemas(schema)
+	}
+
 	content, err = json.Marshal(schema)
 	if err != nil {
 		return err
@@ -105,3 +138,27 @@ func (s *Serve) Setup() error {
 
 	return nil
 }
+
+func (s *Serve) addSchemas(schema map[string]any) {
+	definitions, refPrefix := child(schema, "definitions"), validate.DefinitionsRef
+	if _, ok := schema["openapi"]; ok {
+		definitions, refPrefix = child(child(schema, "components"), "schemas"), "#/components/schemas/"
+	}
+
+	for name, v := range s.schemas {
+		for defName, def := range validate.DefinitionsOf(name, v, refPrefix) {
+			definitions[defName] = def
+		}
+	}
+}
+
+// child returns the object under the key, creating it if there is none.
+func child(parent map[string]any, key string) map[string]any {
+	obj, ok := parent[key].(map[string]any)
+	if !ok {
+		obj = make(map[string]any)
+		parent[key] = obj
+	}
+
+	return obj
+}